@@ -20,6 +20,9 @@ func (association *Association) Error() error  {
 
 // Find find out all related associations
 func (association *Association) Find(value interface{}) *Association {
+	if association.Error() != nil {
+		return association
+	}
 	association.scope.related(value, association.column)
 	return association.setErr(association.scope.db.Error())
 }
@@ -263,6 +266,10 @@ func (association *Association) Clear() *Association {
 
 // Count return the count of current associations
 func (association *Association) Count() int {
+	if association.Error() != nil {
+		return 0
+	}
+
 	var (
 		count        = 0
 		relationship = association.field.Relationship
@@ -1,7 +1,10 @@
 package gorm
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -33,15 +36,17 @@ func updateTimeStampForCreateCallback(scope *Scope) {
 	if !scope.HasError() {
 		now := NowFunc()
 
-		if createdAtField, ok := scope.FieldByName("CreatedAt"); ok {
-			if createdAtField.IsBlank {
-				createdAtField.Set(now)
+		for _, elementScope := range scope.elementScopes() {
+			if createdAtField, ok := elementScope.FieldByName("CreatedAt"); ok {
+				if createdAtField.IsBlank {
+					createdAtField.Set(now)
+				}
 			}
-		}
 
-		if updatedAtField, ok := scope.FieldByName("UpdatedAt"); ok {
-			if updatedAtField.IsBlank {
-				updatedAtField.Set(now)
+			if updatedAtField, ok := elementScope.FieldByName("UpdatedAt"); ok {
+				if updatedAtField.IsBlank {
+					updatedAtField.Set(now)
+				}
 			}
 		}
 	}
@@ -50,6 +55,11 @@ func updateTimeStampForCreateCallback(scope *Scope) {
 // createCallback the callback used to insert data into database
 func createCallback(scope *Scope) {
 	if !scope.HasError() {
+		if scope.IndirectValue().Kind() == reflect.Slice {
+			createBatch(scope)
+			return
+		}
+
 		defer scope.trace(NowFunc())
 
 		var (
@@ -94,6 +104,10 @@ func createCallback(scope *Scope) {
 		}
 
 		lastInsertIDReturningSuffix := scope.Dialect().LastInsertIDReturningSuffix(quotedTableName, returningColumn)
+		conflict, conflictFields, upsert := createConflict(scope, scope, columns)
+		if scope.HasError() {
+			return
+		}
 
 		if len(columns) == 0 {
 			scope.Raw(fmt.Sprintf(
@@ -103,6 +117,13 @@ func createCallback(scope *Scope) {
 				addExtraSpaceIfExist(extraOption),
 				addExtraSpaceIfExist(lastInsertIDReturningSuffix),
 			))
+		} else if upsert {
+			scope.Raw(fmt.Sprintf(
+				"%v%v%v",
+				scope.Dialect().UpsertSQL(quotedTableName, columns, []string{fmt.Sprintf("(%v)", strings.Join(placeholders, ","))}, conflict),
+				addExtraSpaceIfExist(extraOption),
+				addExtraSpaceIfExist(lastInsertIDReturningSuffix),
+			))
 		} else {
 			scope.Raw(fmt.Sprintf(
 				"INSERT INTO %v (%v) VALUES (%v)%v%v",
@@ -114,15 +135,24 @@ func createCallback(scope *Scope) {
 			))
 		}
 
+		if scope.recordDryRun() {
+			return
+		}
+
 		// execute create sql
 		if lastInsertIDReturningSuffix == "" || primaryField == nil {
-			if result, err := scope.SQLDB().Exec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
+			if result, err := scope.sqlExec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
 				// set rows affected count
 				ra, _ := result.RowsAffected()
 				scope.db.SetRowsAffected(ra)
 
 				// set primary value to primary field
-				if primaryField != nil && primaryField.IsBlank {
+				if primaryField != nil && primaryField.IsBlank && upsert {
+					// LastInsertId is unreliable when the conflicted record was updated, find it by conflict columns instead
+					if ra > 0 {
+						scope.Err(findPrimaryKeyByConflict(scope, primaryField, conflictFields))
+					}
+				} else if primaryField != nil && primaryField.IsBlank {
 					if primaryValue, err := result.LastInsertId(); scope.Err(err) == nil {
 						scope.Err(primaryField.Set(primaryValue))
 					}
@@ -130,10 +160,15 @@ func createCallback(scope *Scope) {
 			}
 		} else {
 			if primaryField.Field.CanAddr() {
-				if err := scope.SQLDB().QueryRow(scope.SQL, scope.SQLVars...).Scan(primaryField.Field.Addr().Interface()); scope.Err(err) == nil {
+				err := scope.sqlQueryRow(scope.SQL, scope.SQLVars...).Scan(primaryField.Field.Addr().Interface())
+				if upsert && err == sql.ErrNoRows {
+					// nothing returned as the conflicted record is kept unchanged
+					err = nil
+				} else if err == nil {
 					primaryField.IsBlank = false
 					scope.db.SetRowsAffected(1)
 				}
+				scope.Err(err)
 			} else {
 				scope.Err(ErrUnaddressable)
 			}
@@ -141,6 +176,256 @@ func createCallback(scope *Scope) {
 	}
 }
 
+// createBatch insert all elements of a slice with multi-row INSERT statements, and set auto increment primary keys back to them;
+// elements are grouped by which primary keys and columns having default value are blank, so that blank ones get the database default
+func createBatch(scope *Scope) {
+	var (
+		groups       [][]*Scope
+		groupIndexes = map[string]int{}
+		rowsAffected int64
+	)
+
+	for _, elementScope := range scope.elementScopes() {
+		var blanks []byte
+		for _, field := range elementScope.Fields() {
+			if field.IsNormal && (field.IsPrimaryKey || field.HasDefaultValue) {
+				if field.IsBlank {
+					blanks = append(blanks, '1')
+				} else {
+					blanks = append(blanks, '0')
+				}
+			}
+		}
+
+		if idx, ok := groupIndexes[string(blanks)]; ok {
+			groups[idx] = append(groups[idx], elementScope)
+		} else {
+			groupIndexes[string(blanks)] = len(groups)
+			groups = append(groups, []*Scope{elementScope})
+		}
+	}
+
+	for _, elementScopes := range groups {
+		if createBatchRows(scope, elementScopes); scope.HasError() {
+			return
+		}
+		rowsAffected += scope.db.RowsAffected()
+	}
+	scope.db.SetRowsAffected(rowsAffected)
+}
+
+// createBatchRows insert elements with one multi-row INSERT statement, primary keys and columns having default value are blank in all or none of them
+func createBatchRows(scope *Scope, elementScopes []*Scope) {
+	scope.SQL, scope.SQLVars = "", nil
+	scope.db.SetRowsAffected(0)
+	defer scope.trace(NowFunc())
+
+	var (
+		columns, rows   []string
+		fieldIndexes    []int
+		primaryField    = elementScopes[0].PrimaryField()
+		setPrimaryKey   = primaryField != nil
+		quotedTableName = scope.QuotedTableName()
+		extraOption     string
+		returningSuffix string
+		allBlank        = func(idx int) bool {
+			for _, elementScope := range elementScopes {
+				if !elementScope.Fields()[idx].IsBlank {
+					return false
+				}
+			}
+			return true
+		}
+	)
+
+	for idx, field := range elementScopes[0].Fields() {
+		if field.IsNormal && scope.changeableField(field) {
+			if (field.IsPrimaryKey || field.HasDefaultValue) && allBlank(idx) {
+				continue
+			}
+
+			if field.IsPrimaryKey && primaryField != nil && field.DBName == primaryField.DBName {
+				setPrimaryKey = false
+			}
+			columns = append(columns, scope.Quote(field.DBName))
+			fieldIndexes = append(fieldIndexes, idx)
+		}
+	}
+
+	if len(columns) == 0 {
+		for _, elementScope := range elementScopes {
+			createCallback(elementScope)
+		}
+		scope.db.SetRowsAffected(int64(len(elementScopes)))
+		return
+	}
+
+	for _, elementScope := range elementScopes {
+		var placeholders []string
+		fields := elementScope.Fields()
+		for _, idx := range fieldIndexes {
+			placeholders = append(placeholders, scope.AddToVars(fields[idx].Field.Interface()))
+		}
+		rows = append(rows, fmt.Sprintf("(%v)", strings.Join(placeholders, ",")))
+	}
+
+	if str, ok := scope.Get("gorm:insert_option"); ok {
+		extraOption = fmt.Sprint(str)
+	}
+
+	conflict, _, upsert := createConflict(scope, elementScopes[0], columns)
+	if scope.HasError() {
+		return
+	}
+
+	// skipped records return no primary key, so returned keys couldn't be matched to elements
+	if setPrimaryKey && (!upsert || !conflict.DoNothing) {
+		returningSuffix = scope.Dialect().LastInsertIDReturningSuffix(quotedTableName, scope.Quote(primaryField.DBName))
+	}
+
+	if upsert {
+		scope.Raw(fmt.Sprintf(
+			"%v%v%v",
+			scope.Dialect().UpsertSQL(quotedTableName, columns, rows, conflict),
+			addExtraSpaceIfExist(extraOption),
+			addExtraSpaceIfExist(returningSuffix),
+		))
+	} else {
+		scope.Raw(fmt.Sprintf(
+			"INSERT INTO %v (%v) VALUES %v%v%v",
+			quotedTableName,
+			strings.Join(columns, ","),
+			strings.Join(rows, ","),
+			addExtraSpaceIfExist(extraOption),
+			addExtraSpaceIfExist(returningSuffix),
+		))
+	}
+
+	if scope.recordDryRun() {
+		return
+	}
+
+	if returningSuffix != "" {
+		// dialects like postgres return generated primary keys in insertion order
+		if result, err := scope.sqlQuery(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
+			defer result.Close()
+
+			var count int
+			for result.Next() && count < len(elementScopes) {
+				field := elementScopes[count].PrimaryField()
+				if scope.Err(result.Scan(field.Field.Addr().Interface())) == nil {
+					field.IsBlank = false
+				}
+				count++
+			}
+			scope.Err(result.Err())
+			scope.db.SetRowsAffected(int64(count))
+		}
+		return
+	}
+
+	if result, err := scope.sqlExec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
+		ra, _ := result.RowsAffected()
+		scope.db.SetRowsAffected(ra)
+
+		if setPrimaryKey && !upsert && isIntegerKind(primaryField.Field.Kind()) {
+			// drivers like mssql don't support LastInsertId, primary keys are left blank then
+			if lastInsertID, err := result.LastInsertId(); err == nil {
+				firstInsertID := lastInsertID
+				if !scope.Dialect().BatchLastInsertIDIsFirst() {
+					firstInsertID = lastInsertID - int64(len(elementScopes)) + 1
+				}
+
+				for idx, elementScope := range elementScopes {
+					scope.Err(elementScope.PrimaryField().Set(firstInsertID + int64(idx)))
+				}
+			}
+		}
+	}
+}
+
+// rootConflict move the conflict set by `OnConflict` to instance settings of the root create,
+// associations saved with a new db won't inherit it as their conflict columns might not exist
+func (scope *Scope) rootConflict() *Scope {
+	if value, ok := scope.Get("gorm:on_conflict"); ok {
+		delete(scope.db.Values(), "gorm:on_conflict")
+		scope.InstanceSet("gorm:on_conflict", value)
+	}
+	return scope
+}
+
+// createConflict return the conflict of rootScope with default columns resolved and all columns quoted, and fields of conflict columns
+func createConflict(rootScope *Scope, scope *Scope, columns []string) (conflict Conflict, conflictFields []*Field, ok bool) {
+	value, ok := rootScope.InstanceGet("gorm:on_conflict")
+	if !ok {
+		return
+	}
+
+	option := value.(Conflict)
+	conflict.DoNothing = option.DoNothing
+	if len(option.Columns) == 0 {
+		conflictFields = scope.PrimaryFields()
+	} else {
+		for _, name := range option.Columns {
+			field, found := scope.FieldByName(name)
+			if !found {
+				scope.Err(fmt.Errorf("gorm: unknown conflict column %v", name))
+				return
+			}
+			conflictFields = append(conflictFields, field)
+		}
+	}
+
+	if len(conflictFields) == 0 {
+		scope.Err(errors.New("gorm: conflict columns are required for a model without primary key"))
+		return
+	}
+
+	skipped := map[string]bool{}
+	for _, field := range conflictFields {
+		conflict.Columns = append(conflict.Columns, scope.Quote(field.DBName))
+		skipped[scope.Quote(field.DBName)] = true
+	}
+
+	for _, name := range option.DoUpdates {
+		if field, found := scope.FieldByName(name); found {
+			name = field.DBName
+		}
+		conflict.DoUpdates = append(conflict.DoUpdates, scope.Quote(name))
+	}
+
+	if !conflict.DoNothing && len(conflict.DoUpdates) == 0 {
+		for _, field := range scope.PrimaryFields() {
+			skipped[scope.Quote(field.DBName)] = true
+		}
+		if field, found := scope.FieldByName("CreatedAt"); found {
+			skipped[scope.Quote(field.DBName)] = true
+		}
+
+		for _, column := range columns {
+			if !skipped[column] {
+				conflict.DoUpdates = append(conflict.DoUpdates, column)
+			}
+		}
+		conflict.DoNothing = len(conflict.DoUpdates) == 0
+	}
+	return
+}
+
+// findPrimaryKeyByConflict set primary key of the record matching conflict columns
+func findPrimaryKeyByConflict(scope *Scope, primaryField *Field, conflictFields []*Field) error {
+	conditions := map[string]interface{}{}
+	for _, field := range conflictFields {
+		conditions[field.DBName] = field.Field.Interface()
+	}
+
+	if err := scope.NewDB().Table(scope.TableName()).Select(scope.Quote(primaryField.DBName)).Where(conditions).Row().Scan(primaryField.Field.Addr().Interface()); err != nil {
+		return err
+	}
+	primaryField.IsBlank = false
+	return nil
+}
+
 // forceReloadAfterCreateCallback will reload columns that having default value, and set it back to current object
 func forceReloadAfterCreateCallback(scope *Scope) {
 	if blankColumnsWithDefaultValue, ok := scope.InstanceGet("gorm:blank_columns_with_default_value"); ok {
@@ -10,7 +10,7 @@ import (
 
 // preloadCallback used to preload associations
 func preloadCallback(scope *Scope) {
-	if _, skip := scope.InstanceGet("gorm:skip_query_callback"); skip {
+	if _, skip := scope.InstanceGet("gorm:skip_query_callback"); skip || scope.isDryRun() {
 		return
 	}
 
@@ -107,21 +107,184 @@ func autoPreload(scope *Scope) {
 	}
 }
 
-func (scope *Scope) generatePreloadDBWithConditions(conditions []interface{}) (Repository, []interface{}) {
+// PreloadOptions options of preloaded associations, passed as a condition of `Preload`
+//     // preload the latest 5 comments of each post, without their bodies
+//     db.Preload("Comments", gorm.PreloadOptions{Order: "created_at DESC", Limit: 5, Select: []string{"id", "title"}}).Find(&posts)
+type PreloadOptions struct {
+	// Order order of preloaded records, e.g. `created_at DESC`
+	Order string
+	// Limit max count of records preloaded for each record of has many and many to many associations, which is applied with
+	// `ROW_NUMBER()` if the dialect supports window functions, otherwise extra records are discarded after querying
+	Limit int
+	// Select columns of preloaded records, foreign keys required to assign them are selected as well
+	Select []string
+	// Unscoped preload soft deleted records
+	Unscoped bool
+}
+
+// supportWindowFunction check window functions with the dialect of the parent db, which is kept by all its clones and caches the
+// result, so the database isn't queried for every preload, nor in dry run
+func (scope *Scope) supportWindowFunction() bool {
+	if parent := scope.db.Parent(); parent != nil && parent.Dialect() != nil {
+		return parent.Dialect().SupportWindowFunction()
+	}
+	return scope.Dialect().SupportWindowFunction()
+}
+
+func (scope *Scope) generatePreloadDBWithConditions(conditions []interface{}) (Repository, []interface{}, PreloadOptions) {
 	var (
 		preloadDB         = scope.NewDB()
 		preloadConditions []interface{}
+		options           PreloadOptions
 	)
 
 	for _, condition := range conditions {
-		if scopes, ok := condition.(func(Repository) Repository); ok {
-			preloadDB = scopes(preloadDB)
-		} else {
+		switch value := condition.(type) {
+		case func(Repository) Repository:
+			preloadDB = value(preloadDB)
+		case PreloadOptions:
+			options = value
+		case *PreloadOptions:
+			if value != nil {
+				options = *value
+			}
+		default:
 			preloadConditions = append(preloadConditions, condition)
 		}
 	}
 
-	return preloadDB, preloadConditions
+	if options.Unscoped {
+		preloadDB = preloadDB.Unscoped()
+	}
+	return preloadDB, preloadConditions, options
+}
+
+// selectColumns return columns of `Select` and keys not selected, qualified by the quoted table name, empty if no column selected
+func (options PreloadOptions) selectColumns(scope *Scope, quotedTableName string, keys []string) []string {
+	if len(options.Select) == 0 {
+		return nil
+	}
+
+	var (
+		columns  []string
+		selected = map[string]bool{}
+	)
+	for _, column := range options.Select {
+		selected[column] = true
+		if identifierRegexp.MatchString(column) && !strings.Contains(column, ".") {
+			column = quotedTableName + "." + scope.Quote(column)
+		}
+		columns = append(columns, column)
+	}
+
+	for _, key := range keys {
+		if !selected[key] {
+			columns = append(columns, quotedTableName+"."+scope.Quote(key))
+		}
+	}
+	return columns
+}
+
+// applyTo apply order and selected columns of options to preload db, keys are columns required to assign preloaded records
+func (options PreloadOptions) applyTo(scope *Scope, preloadDB Repository, quotedTableName string, keys []string) Repository {
+	if options.Order != "" {
+		preloadDB = preloadDB.Order(options.Order)
+	}
+	if columns := options.selectColumns(scope, quotedTableName, keys); len(columns) > 0 {
+		preloadDB = preloadDB.Select(columns)
+	}
+	return preloadDB
+}
+
+// limitPerParent limit records queried by preload db to `Limit` for each parent record with `ROW_NUMBER()`, records are partitioned
+// by partitionColumns and ordered by `Order` or primary keys of the preloaded table
+func (options PreloadOptions) limitPerParent(scope *Scope, preloadDB Repository, preloadScope *Scope, columns []string, partitionColumns []string) Repository {
+	order := options.Order
+	if order == "" {
+		var primaryKeys []string
+		for _, field := range preloadScope.PrimaryFields() {
+			primaryKeys = append(primaryKeys, preloadScope.QuotedTableName()+"."+scope.Quote(field.DBName))
+		}
+		order = strings.Join(primaryKeys, ",")
+	}
+
+	columns = append(columns, fmt.Sprintf("ROW_NUMBER() OVER (PARTITION BY %v ORDER BY %v) AS gorm_preload_row", strings.Join(partitionColumns, ","), order))
+	subQuery := preloadDB.Select(columns).SubQuery()
+	return scope.NewDB().Unscoped().Raw("SELECT * FROM ? AS gorm_preload WHERE gorm_preload_row <= ? ORDER BY gorm_preload_row", subQuery, options.Limit)
+}
+
+// preloadReservedBindVars bind vars reserved for preload conditions when splitting keys of preload queries into chunks
+const preloadReservedBindVars = 100
+
+// preloadChunkSize return max count of keys of given columns in a preload query, so that its bind vars don't exceed
+// `Dialect.MaxBindVars`, 0 if unlimited
+func (scope *Scope) preloadChunkSize(columns int) int {
+	maxBindVars := scope.Dialect().MaxBindVars()
+	if maxBindVars <= 0 || columns <= 0 {
+		return 0
+	}
+
+	if size := (maxBindVars - preloadReservedBindVars) / columns; size > 0 {
+		return size
+	}
+	return 1
+}
+
+// splitPreloadKeys remove duplicated keys, and split keys of given columns into chunks whose bind vars fit in a preload query
+func (scope *Scope) splitPreloadKeys(keys [][]interface{}, columns int) (chunks [][][]interface{}) {
+	var (
+		uniqueKeys [][]interface{}
+		hashedKeys = map[string]bool{}
+	)
+	for _, key := range keys {
+		if hashedKey := toString(key); !hashedKeys[hashedKey] {
+			hashedKeys[hashedKey] = true
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+
+	size := scope.preloadChunkSize(columns)
+	for size > 0 && len(uniqueKeys) > size {
+		chunks = append(chunks, uniqueKeys[:size])
+		uniqueKeys = uniqueKeys[size:]
+	}
+	return append(chunks, uniqueKeys)
+}
+
+// splitPreloadSources remove records of scope with duplicated values of fields, and split records into chunks whose keys fit in a
+// preload query, chunks are pointers of slices
+func (scope *Scope) splitPreloadSources(fieldNames []string) (chunks []interface{}) {
+	indirectScopeValue := scope.IndirectValue()
+	if indirectScopeValue.Kind() != reflect.Slice {
+		return []interface{}{scope.Value}
+	}
+
+	var (
+		size       = scope.preloadChunkSize(len(fieldNames))
+		chunk      = reflect.New(indirectScopeValue.Type())
+		hashedKeys = map[string]bool{}
+	)
+	for i := 0; i < indirectScopeValue.Len(); i++ {
+		object := indirectScopeValue.Index(i)
+		hashedKey := toString(getValueFromFields(indirect(object), fieldNames))
+		if hashedKeys[hashedKey] {
+			continue
+		}
+		hashedKeys[hashedKey] = true
+
+		if size > 0 && chunk.Elem().Len() >= size {
+			chunks = append(chunks, chunk.Interface())
+			chunk = reflect.New(indirectScopeValue.Type())
+		}
+		chunk.Elem().Set(reflect.Append(chunk.Elem(), object))
+	}
+	return append(chunks, chunk.Interface())
+}
+
+// appendPreloadResults append records of a chunk to results, both are pointers of slices made by `makeSlice`
+func appendPreloadResults(results interface{}, chunkResults interface{}) {
+	resultsValue := indirect(reflect.ValueOf(results))
+	resultsValue.Set(reflect.AppendSlice(resultsValue, indirect(reflect.ValueOf(chunkResults))))
 }
 
 // handleHasOnePreload used to preload has one associations
@@ -135,18 +298,24 @@ func (scope *Scope) handleHasOnePreload(field *Field, conditions []interface{})
 	}
 
 	// preload conditions
-	preloadDB, preloadConditions := scope.generatePreloadDBWithConditions(conditions)
-
-	// find relations
-	query := fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.ForeignDBNames), toQueryMarks(primaryKeys))
-	values := toQueryValues(primaryKeys)
-	if relation.PolymorphicType != "" {
-		query += fmt.Sprintf(" AND %v = ?", scope.Quote(relation.PolymorphicDBName))
-		values = append(values, relation.PolymorphicValue)
-	}
+	preloadDB, preloadConditions, options := scope.generatePreloadDBWithConditions(conditions)
 
+	// find relations in chunks of primary keys
 	results := makeSlice(field.Struct.Type)
-	scope.Err(preloadDB.Where(query, values...).Find(results, preloadConditions...).Error())
+	preloadDB = options.applyTo(scope, preloadDB, scope.New(results).QuotedTableName(), relation.ForeignDBNames)
+
+	for _, keys := range scope.splitPreloadKeys(primaryKeys, len(relation.ForeignDBNames)) {
+		query := fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.ForeignDBNames), toQueryMarks(keys))
+		values := toQueryValues(keys)
+		if relation.PolymorphicType != "" {
+			query += fmt.Sprintf(" AND %v = ?", scope.Quote(relation.PolymorphicDBName))
+			values = append(values, relation.PolymorphicValue)
+		}
+
+		chunkResults := makeSlice(field.Struct.Type)
+		scope.Err(preloadDB.Where(query, values...).Find(chunkResults, preloadConditions...).Error())
+		appendPreloadResults(results, chunkResults)
+	}
 
 	// assign find results
 	var (
@@ -184,30 +353,68 @@ func (scope *Scope) handleHasManyPreload(field *Field, conditions []interface{})
 	}
 
 	// preload conditions
-	preloadDB, preloadConditions := scope.generatePreloadDBWithConditions(conditions)
+	preloadDB, preloadConditions, options := scope.generatePreloadDBWithConditions(conditions)
+
+	var (
+		results            = makeSlice(field.Struct.Type)
+		preloadScope       = scope.New(results)
+		quotedTableName    = preloadScope.QuotedTableName()
+		indirectScopeValue = scope.IndirectValue()
+		limitPerParent     = options.Limit > 0 && indirectScopeValue.Kind() == reflect.Slice && scope.supportWindowFunction()
+	)
 
-	// find relations
-	query := fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.ForeignDBNames), toQueryMarks(primaryKeys))
-	values := toQueryValues(primaryKeys)
-	if relation.PolymorphicType != "" {
-		query += fmt.Sprintf(" AND %v = ?", scope.Quote(relation.PolymorphicDBName))
-		values = append(values, relation.PolymorphicValue)
+	if limitPerParent {
+		if len(preloadConditions) > 0 {
+			preloadDB = preloadDB.Where(preloadConditions[0], preloadConditions[1:]...)
+			preloadConditions = nil
+		}
+	} else {
+		preloadDB = options.applyTo(scope, preloadDB, quotedTableName, relation.ForeignDBNames)
+		if options.Limit > 0 && indirectScopeValue.Kind() != reflect.Slice {
+			preloadDB = preloadDB.Limit(options.Limit)
+		}
 	}
 
-	results := makeSlice(field.Struct.Type)
-	scope.Err(preloadDB.Where(query, values...).Find(results, preloadConditions...).Error())
+	// find relations in chunks of primary keys
+	for _, keys := range scope.splitPreloadKeys(primaryKeys, len(relation.ForeignDBNames)) {
+		query := fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.ForeignDBNames), toQueryMarks(keys))
+		values := toQueryValues(keys)
+		if relation.PolymorphicType != "" {
+			query += fmt.Sprintf(" AND %v = ?", scope.Quote(relation.PolymorphicDBName))
+			values = append(values, relation.PolymorphicValue)
+		}
+
+		chunkDB := preloadDB.Where(query, values...)
+		if limitPerParent {
+			columns := options.selectColumns(scope, quotedTableName, relation.ForeignDBNames)
+			if len(columns) == 0 {
+				columns = []string{quotedTableName + ".*"}
+			}
+
+			var partitionColumns []string
+			for _, dbName := range relation.ForeignDBNames {
+				partitionColumns = append(partitionColumns, quotedTableName+"."+scope.Quote(dbName))
+			}
+			chunkDB = options.limitPerParent(scope, chunkDB.Model(results), preloadScope, columns, partitionColumns)
+		}
+
+		chunkResults := makeSlice(field.Struct.Type)
+		scope.Err(chunkDB.Find(chunkResults, preloadConditions...).Error())
+		appendPreloadResults(results, chunkResults)
+	}
 
 	// assign find results
-	var (
-		resultsValue       = indirect(reflect.ValueOf(results))
-		indirectScopeValue = scope.IndirectValue()
-	)
+	resultsValue := indirect(reflect.ValueOf(results))
 
 	if indirectScopeValue.Kind() == reflect.Slice {
 		preloadMap := make(map[string][]reflect.Value)
 		for i := 0; i < resultsValue.Len(); i++ {
 			result := resultsValue.Index(i)
 			foreignValues := getValueFromFields(result, relation.ForeignFieldNames)
+			// discard records over the limit if the dialect doesn't support window functions
+			if options.Limit > 0 && len(preloadMap[toString(foreignValues)]) >= options.Limit {
+				continue
+			}
 			preloadMap[toString(foreignValues)] = append(preloadMap[toString(foreignValues)], result)
 		}
 
@@ -231,7 +438,7 @@ func (scope *Scope) handleBelongsToPreload(field *Field, conditions []interface{
 	relation := field.Relationship
 
 	// preload conditions
-	preloadDB, preloadConditions := scope.generatePreloadDBWithConditions(conditions)
+	preloadDB, preloadConditions, options := scope.generatePreloadDBWithConditions(conditions)
 
 	// get relations's primary keys
 	primaryKeys := scope.getColumnAsArray(relation.ForeignFieldNames, scope.Value)
@@ -239,9 +446,15 @@ func (scope *Scope) handleBelongsToPreload(field *Field, conditions []interface{
 		return
 	}
 
-	// find relations
+	// find relations in chunks of primary keys
 	results := makeSlice(field.Struct.Type)
-	scope.Err(preloadDB.Where(fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.AssociationForeignDBNames), toQueryMarks(primaryKeys)), toQueryValues(primaryKeys)...).Find(results, preloadConditions...).Error())
+	preloadDB = options.applyTo(scope, preloadDB, scope.New(results).QuotedTableName(), relation.AssociationForeignDBNames)
+
+	for _, keys := range scope.splitPreloadKeys(primaryKeys, len(relation.AssociationForeignDBNames)) {
+		chunkResults := makeSlice(field.Struct.Type)
+		scope.Err(preloadDB.Where(fmt.Sprintf("%v IN (%v)", toQueryCondition(scope, relation.AssociationForeignDBNames), toQueryMarks(keys)), toQueryValues(keys)...).Find(chunkResults, preloadConditions...).Error())
+		appendPreloadResults(results, chunkResults)
+	}
 
 	// assign find results
 	var (
@@ -287,83 +500,123 @@ func (scope *Scope) handleManyToManyPreload(field *Field, conditions []interface
 		sourceKeys = append(sourceKeys, key.DBName)
 	}
 
+	var foreignFieldNames = []string{}
+	for _, dbName := range relation.ForeignFieldNames {
+		if field, ok := scope.FieldByName(dbName); ok {
+			foreignFieldNames = append(foreignFieldNames, field.Name)
+		}
+	}
+
 	// preload conditions
-	preloadDB, preloadConditions := scope.generatePreloadDBWithConditions(conditions)
+	preloadDB, preloadConditions, options := scope.generatePreloadDBWithConditions(conditions)
 
 	// generate query with join table
-	newScope := scope.New(reflect.New(fieldType).Interface())
+	var (
+		newScope            = scope.New(reflect.New(fieldType).Interface())
+		quotedTableName     = newScope.QuotedTableName()
+		quotedJoinTableName = scope.Quote(joinTableHandler.Table(scope.db))
+		joinTableKeys       []string
+		isSlice             = scope.IndirectValue().Kind() == reflect.Slice
+		limitPerParent      = options.Limit > 0 && isSlice && scope.supportWindowFunction()
+	)
 	preloadDB = preloadDB.Table(newScope.TableName()).Model(newScope.Value)
 
+	for _, sourceKey := range sourceKeys {
+		joinTableKeys = append(joinTableKeys, quotedJoinTableName+"."+scope.Quote(sourceKey))
+	}
+
+	selects := options.selectColumns(scope, quotedTableName, nil)
+	if len(selects) > 0 {
+		selects = append(selects, joinTableKeys...)
+		preloadDB = preloadDB.Select(selects)
+	}
+
 	if len(preloadDB.Search().selects) == 0 {
 		preloadDB = preloadDB.Select("*")
 	}
 
-	preloadDB = joinTableHandler.JoinWith(joinTableHandler, preloadDB, scope.Value)
-
-	// preload inline conditions
-	if len(preloadConditions) > 0 {
-		preloadDB = preloadDB.Where(preloadConditions[0], preloadConditions[1:]...)
+	if !limitPerParent {
+		if options.Order != "" {
+			preloadDB = preloadDB.Order(options.Order)
+		}
+		if options.Limit > 0 && !isSlice {
+			preloadDB = preloadDB.Limit(options.Limit)
+		}
 	}
 
-	rows, err := preloadDB.Rows()
+	// find relations in chunks of source records
+	for _, source := range scope.splitPreloadSources(foreignFieldNames) {
+		chunkDB := joinTableHandler.JoinWith(joinTableHandler, preloadDB, source)
 
-	if scope.Err(err) != nil {
-		return
-	}
-	defer rows.Close()
+		// preload inline conditions
+		if len(preloadConditions) > 0 {
+			chunkDB = chunkDB.Where(preloadConditions[0], preloadConditions[1:]...)
+		}
 
-	columns, _ := rows.Columns()
-	for rows.Next() {
-		var (
-			elem   = reflect.New(fieldType).Elem()
-			fields = scope.New(elem.Addr().Interface()).Fields()
-		)
+		if limitPerParent {
+			if len(selects) == 0 {
+				selects = append([]string{quotedTableName + ".*"}, joinTableKeys...)
+			}
+			chunkDB = options.limitPerParent(scope, chunkDB, newScope, selects, joinTableKeys)
+		}
 
-		// register foreign keys in join tables
-		var joinTableFields []*Field
-		for _, sourceKey := range sourceKeys {
-			joinTableFields = append(joinTableFields, &Field{StructField: &StructField{DBName: sourceKey, IsNormal: true}, Field: reflect.New(foreignKeyType).Elem()})
+		rows, err := chunkDB.Rows()
+		if scope.Err(err) != nil {
+			return
 		}
 
-		scope.scan(rows, columns, append(fields, joinTableFields...))
+		columns, _ := rows.Columns()
+		for rows.Next() {
+			var (
+				elem   = reflect.New(fieldType).Elem()
+				fields = scope.New(elem.Addr().Interface()).Fields()
+			)
+
+			// register foreign keys in join tables
+			var joinTableFields []*Field
+			for _, sourceKey := range sourceKeys {
+				joinTableFields = append(joinTableFields, &Field{StructField: &StructField{DBName: sourceKey, IsNormal: true}, Field: reflect.New(foreignKeyType).Elem()})
+			}
+
+			scope.scan(rows, columns, append(fields, joinTableFields...))
 
-		scope.New(elem.Addr().Interface()).
-			InstanceSet("gorm:skip_query_callback", true).
-			callCallbacks(scope.db.Parent().Callback().queries)
+			scope.New(elem.Addr().Interface()).
+				InstanceSet("gorm:skip_query_callback", true).
+				callCallbacks(scope.db.Parent().Callback().queries)
 
-		var foreignKeys = make([]interface{}, len(sourceKeys))
-		// generate hashed forkey keys in join table
-		for idx, joinTableField := range joinTableFields {
-			if !joinTableField.Field.IsNil() {
-				foreignKeys[idx] = joinTableField.Field.Elem().Interface()
+			var foreignKeys = make([]interface{}, len(sourceKeys))
+			// generate hashed forkey keys in join table
+			for idx, joinTableField := range joinTableFields {
+				if !joinTableField.Field.IsNil() {
+					foreignKeys[idx] = joinTableField.Field.Elem().Interface()
+				}
 			}
-		}
-		hashedSourceKeys := toString(foreignKeys)
+			hashedSourceKeys := toString(foreignKeys)
 
-		if isPtr {
-			linkHash[hashedSourceKeys] = append(linkHash[hashedSourceKeys], elem.Addr())
-		} else {
-			linkHash[hashedSourceKeys] = append(linkHash[hashedSourceKeys], elem)
+			// discard records over the limit if the dialect doesn't support window functions
+			if options.Limit > 0 && len(linkHash[hashedSourceKeys]) >= options.Limit {
+				continue
+			}
+
+			if isPtr {
+				linkHash[hashedSourceKeys] = append(linkHash[hashedSourceKeys], elem.Addr())
+			} else {
+				linkHash[hashedSourceKeys] = append(linkHash[hashedSourceKeys], elem)
+			}
 		}
-	}
 
-	if err := rows.Err(); err != nil {
-		scope.Err(err)
+		if err := rows.Err(); err != nil {
+			scope.Err(err)
+		}
+		rows.Close()
 	}
 
 	// assign find results
 	var (
 		indirectScopeValue = scope.IndirectValue()
 		fieldsSourceMap    = map[string][]reflect.Value{}
-		foreignFieldNames  = []string{}
 	)
 
-	for _, dbName := range relation.ForeignFieldNames {
-		if field, ok := scope.FieldByName(dbName); ok {
-			foreignFieldNames = append(foreignFieldNames, field.Name)
-		}
-	}
-
 	if indirectScopeValue.Kind() == reflect.Slice {
 		for j := 0; j < indirectScopeValue.Len(); j++ {
 			object := indirect(indirectScopeValue.Index(j))
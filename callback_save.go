@@ -65,6 +65,12 @@ func saveAssociationCheck(scope *Scope, field *Field) (autoUpdate bool, autoCrea
 }
 
 func saveBeforeAssociationsCallback(scope *Scope) {
+	for _, elementScope := range scope.elementScopes() {
+		saveBeforeAssociations(elementScope)
+	}
+}
+
+func saveBeforeAssociations(scope *Scope) {
 	for _, field := range scope.Fields() {
 		autoUpdate, autoCreate, saveReference, relationship := saveAssociationCheck(scope, field)
 
@@ -96,6 +102,12 @@ func saveBeforeAssociationsCallback(scope *Scope) {
 }
 
 func saveAfterAssociationsCallback(scope *Scope) {
+	for _, elementScope := range scope.elementScopes() {
+		saveAfterAssociations(elementScope)
+	}
+}
+
+func saveAfterAssociations(scope *Scope) {
 	for _, field := range scope.Fields() {
 		autoUpdate, autoCreate, saveReference, relationship := saveAssociationCheck(scope, field)
 
@@ -0,0 +1,65 @@
+// Command gormdrift check a database against a schema snapshot written by `Snapshot.WriteJSON`, and exit with status 1 if it drifted, e.g:
+//     gormdrift -dialect postgres -dsn "host=localhost user=gorm dbname=gorm sslmode=disable" -snapshot schema.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+	_ "gorm.io/gorm/dialects/mssql"
+	_ "gorm.io/gorm/dialects/mysql"
+	_ "gorm.io/gorm/dialects/postgres"
+	_ "gorm.io/gorm/dialects/sqlite"
+)
+
+func main() {
+	var (
+		dialect  = flag.String("dialect", "sqlite3", "dialect of the database, one of mysql, postgres, mssql, sqlite3")
+		dsn      = flag.String("dsn", "", "data source name of the database")
+		snapshot = flag.String("snapshot", "", "schema snapshot file to check the database against")
+	)
+	flag.Parse()
+
+	if *dsn == "" || *snapshot == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Open(*snapshot)
+	if err != nil {
+		fail(err)
+	}
+	defer file.Close()
+
+	schema, err := gorm.ReadSnapshot(file)
+	if err != nil {
+		fail(err)
+	}
+
+	db, err := gorm.Open(*dialect, *dsn)
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+
+	drifts, err := gorm.CheckDrift(db, schema)
+	if err != nil {
+		fail(err)
+	}
+
+	for _, drift := range drifts {
+		fmt.Println(drift)
+	}
+
+	if len(drifts) > 0 {
+		fmt.Fprintf(os.Stderr, "gormdrift: %d differences found\n", len(drifts))
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "gormdrift: %v\n", err)
+	os.Exit(1)
+}
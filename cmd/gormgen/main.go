@@ -0,0 +1,62 @@
+// Command gormgen generate models with gorm tags from tables of an existing database, e.g:
+//     gormgen -dialect postgres -dsn "host=localhost user=gorm dbname=gorm sslmode=disable" -package models -out models/models.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+	_ "gorm.io/gorm/dialects/mssql"
+	_ "gorm.io/gorm/dialects/mysql"
+	_ "gorm.io/gorm/dialects/postgres"
+	_ "gorm.io/gorm/dialects/sqlite"
+)
+
+func main() {
+	var (
+		dialect     = flag.String("dialect", "sqlite3", "dialect of the database, one of mysql, postgres, mssql, sqlite3")
+		dsn         = flag.String("dsn", "", "data source name of the database")
+		packageName = flag.String("package", "models", "package name of generated source")
+		tables      = flag.String("tables", "", "comma separated tables to generate models for, all tables by default")
+		out         = flag.String("out", "", "file to write generated source to, stdout by default")
+	)
+	flag.Parse()
+
+	if *dsn == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := gorm.Open(*dialect, *dsn)
+	if err != nil {
+		fail(err)
+	}
+	defer db.Close()
+
+	options := gorm.GenerateOptions{Package: *packageName}
+	if *tables != "" {
+		for _, table := range strings.Split(*tables, ",") {
+			options.Tables = append(options.Tables, strings.TrimSpace(table))
+		}
+	}
+
+	source, err := gorm.GenerateModels(db, options)
+	if err != nil {
+		fail(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(source)
+	} else if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "gormgen: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,284 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition a group of conditions joined with `AND` or `OR`, or negated with `NOT`, which is rendered in parentheses so it keeps its
+// precedence when combined with other conditions; conditions could be anything accepted by `Where`, including `*Expression` carrying
+// arguments, nested groups, a `func(Repository) Repository` adding conditions to a new db, or a `*Search` whose conditions are grouped
+//     // SELECT * FROM users WHERE ((name = 'jinzhu') OR (age > 18)) AND (tenant_id = 1)
+//     db.Where(gorm.Or(gorm.Expr("name = ?", "jinzhu"), "age > 18")).Where("tenant_id = ?", 1).Find(&users)
+type Condition struct {
+	operator   string
+	conditions []interface{}
+}
+
+// And group conditions that must all match
+func And(conditions ...interface{}) *Condition {
+	return &Condition{operator: "AND", conditions: conditions}
+}
+
+// Or group conditions that match if any of them matches
+func Or(conditions ...interface{}) *Condition {
+	return &Condition{operator: "OR", conditions: conditions}
+}
+
+// Not group conditions that match if they don't all match
+func Not(conditions ...interface{}) *Condition {
+	return &Condition{operator: "NOT", conditions: conditions}
+}
+
+// buildGroupCondition build SQL of a condition group, empty conditions are skipped, empty if there is no condition
+func (scope *Scope) buildGroupCondition(condition *Condition, include bool) string {
+	var sqls []string
+	for _, query := range condition.conditions {
+		if sql := scope.buildCondition(map[string]interface{}{"query": query, "args": []interface{}{}}, true); sql != "" {
+			sqls = append(sqls, sql)
+		}
+	}
+
+	if len(sqls) == 0 {
+		return ""
+	}
+
+	var sql string
+	if condition.operator == "OR" {
+		sql = fmt.Sprintf("(%v)", strings.Join(sqls, " OR "))
+	} else {
+		sql = fmt.Sprintf("(%v)", strings.Join(sqls, " AND "))
+	}
+
+	if (condition.operator == "NOT") == include {
+		sql = fmt.Sprintf("(NOT %v)", sql)
+	}
+	return sql
+}
+
+// buildSearchCondition build SQL of where, or and not conditions of a search in parentheses, refer `whereSQL`
+func (scope *Scope) buildSearchCondition(search *Search, include bool) string {
+	if search == nil {
+		return ""
+	}
+
+	sql := scope.searchConditionsSQL(search)
+	if sql == "" {
+		return ""
+	}
+
+	if !include {
+		return fmt.Sprintf("(NOT (%v))", sql)
+	}
+	return fmt.Sprintf("(%v)", sql)
+}
+
+// searchConditionsSQL combine where and not conditions of a search with `AND`, then or conditions with `OR`
+func (scope *Scope) searchConditionsSQL(search *Search) string {
+	var andConditions, orConditions []string
+
+	for _, clause := range search.whereConditions {
+		if sql := scope.buildCondition(clause, true); sql != "" {
+			andConditions = append(andConditions, sql)
+		}
+	}
+
+	for _, clause := range search.orConditions {
+		if sql := scope.buildCondition(clause, true); sql != "" {
+			orConditions = append(orConditions, sql)
+		}
+	}
+
+	for _, clause := range search.notConditions {
+		if sql := scope.buildCondition(clause, false); sql != "" {
+			andConditions = append(andConditions, sql)
+		}
+	}
+
+	orSQL := strings.Join(orConditions, " OR ")
+	combinedSQL := strings.Join(andConditions, " AND ")
+	if len(combinedSQL) > 0 {
+		if len(orSQL) > 0 {
+			combinedSQL = combinedSQL + " OR " + orSQL
+		}
+	} else {
+		combinedSQL = orSQL
+	}
+	return combinedSQL
+}
+
+// Column a column to build conditions without SQL strings, the name is a column name or field name of the model, which is validated
+// and quoted; names of other tables' columns like `companies.name` are quoted without validation
+//     // SELECT * FROM "users" WHERE ("users"."age" BETWEEN 18 AND 30) AND ("users"."name" LIKE 'jin%')
+//     db.Where(gorm.Col("age").Between(18, 30)).Where(gorm.Col("Name").Like("jin%")).Find(&users)
+type Column struct {
+	name string
+}
+
+// ColumnCondition a condition on a column built by `Column`'s operators
+type ColumnCondition struct {
+	column   string
+	operator string
+	values   []interface{}
+}
+
+// Col return column of the name to build conditions
+func Col(name string) Column {
+	return Column{name: name}
+}
+
+// Eq match column equal to value, or `IS NULL` if value is nil
+func (column Column) Eq(value interface{}) *ColumnCondition {
+	if value == nil {
+		return column.IsNull()
+	}
+	return &ColumnCondition{column: column.name, operator: "=", values: []interface{}{value}}
+}
+
+// Neq match column not equal to value, or `IS NOT NULL` if value is nil
+func (column Column) Neq(value interface{}) *ColumnCondition {
+	if value == nil {
+		return column.IsNotNull()
+	}
+	return &ColumnCondition{column: column.name, operator: "<>", values: []interface{}{value}}
+}
+
+// Gt match column greater than value
+func (column Column) Gt(value interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: ">", values: []interface{}{value}}
+}
+
+// Gte match column greater than or equal to value
+func (column Column) Gte(value interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: ">=", values: []interface{}{value}}
+}
+
+// Lt match column less than value
+func (column Column) Lt(value interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "<", values: []interface{}{value}}
+}
+
+// Lte match column less than or equal to value
+func (column Column) Lte(value interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "<=", values: []interface{}{value}}
+}
+
+// Between match column between from and to, inclusive
+func (column Column) Between(from interface{}, to interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "BETWEEN", values: []interface{}{from, to}}
+}
+
+// Like match column with pattern, `%` matches any characters and `_` matches a character
+func (column Column) Like(pattern string) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "LIKE", values: []interface{}{pattern}}
+}
+
+// NotLike match column not matching pattern
+func (column Column) NotLike(pattern string) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "NOT LIKE", values: []interface{}{pattern}}
+}
+
+// In match column in values, which is a slice or a sub query built by `SubQuery`, e.g:
+//     db.Where(gorm.Col("id").In(db.Table("orders").Select("user_id").SubQuery()))
+func (column Column) In(values interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "IN", values: []interface{}{values}}
+}
+
+// NotIn match column not in values, which is a slice or a sub query built by `SubQuery`
+func (column Column) NotIn(values interface{}) *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "NOT IN", values: []interface{}{values}}
+}
+
+// IsNull match column is null
+func (column Column) IsNull() *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "IS NULL"}
+}
+
+// IsNotNull match column is not null
+func (column Column) IsNotNull() *ColumnCondition {
+	return &ColumnCondition{column: column.name, operator: "IS NOT NULL"}
+}
+
+// columnConditionDBName return column name of a column condition, which is a field's name or column name of the model, or a column
+// qualified by another table's name; table name is empty for the model's columns
+func (scope *Scope) columnConditionDBName(name string) (tableName string, column string, err error) {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		tableName, column = name[:idx], name[idx+1:]
+		if tableName != scope.TableName() {
+			for _, part := range append(strings.Split(tableName, "."), column) {
+				if !identifierRegexp.MatchString(part) {
+					return "", "", fmt.Errorf("gorm: invalid column %v", name)
+				}
+			}
+			return tableName, column, nil
+		}
+		name = column
+	}
+
+	fields := scope.GetModelStruct().StructFields
+	for _, field := range fields {
+		if field.IsNormal && (field.DBName == name || field.Name == name) {
+			return "", field.DBName, nil
+		}
+	}
+
+	if len(fields) == 0 && identifierRegexp.MatchString(name) {
+		return "", name, nil
+	}
+	return "", "", fmt.Errorf("gorm: invalid column %v for table %v", name, scope.TableName())
+}
+
+// buildColumnCondition build SQL of a column condition, the column is quoted and qualified by table name
+func (scope *Scope) buildColumnCondition(condition *ColumnCondition, include bool) string {
+	tableName, column, err := scope.columnConditionDBName(condition.column)
+	if scope.Err(err) != nil {
+		return ""
+	}
+
+	quotedColumn := scope.QuotedTableName() + "." + scope.Quote(column)
+	if tableName != "" {
+		quotedColumn = scope.Quote(tableName) + "." + scope.Quote(column)
+	}
+
+	var sql string
+	switch condition.operator {
+	case "IS NULL", "IS NOT NULL":
+		sql = fmt.Sprintf("%v %v", quotedColumn, condition.operator)
+	case "BETWEEN":
+		sql = fmt.Sprintf("%v BETWEEN %v AND %v", quotedColumn, scope.AddToVars(condition.values[0]), scope.AddToVars(condition.values[1]))
+	case "IN", "NOT IN":
+		switch values := condition.values[0].(type) {
+		case *Expression:
+			expr := scope.AddToVars(values)
+			if !strings.HasPrefix(strings.TrimSpace(expr), "(") {
+				expr = "(" + expr + ")"
+			}
+			sql = fmt.Sprintf("%v %v %v", quotedColumn, condition.operator, expr)
+		default:
+			reflectValue := reflect.ValueOf(values)
+			if _, ok := values.(driver.Valuer); ok || reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array || reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+				sql = fmt.Sprintf("%v %v (%v)", quotedColumn, condition.operator, scope.AddToVars(values))
+			} else if reflectValue.Len() == 0 {
+				if condition.operator == "NOT IN" {
+					return ""
+				}
+				sql = fmt.Sprintf("%v IN (NULL)", quotedColumn)
+			} else {
+				var marks []string
+				for i := 0; i < reflectValue.Len(); i++ {
+					marks = append(marks, scope.AddToVars(reflectValue.Index(i).Interface()))
+				}
+				sql = fmt.Sprintf("%v %v (%v)", quotedColumn, condition.operator, strings.Join(marks, ","))
+			}
+		}
+	default:
+		sql = fmt.Sprintf("%v %v %v", quotedColumn, condition.operator, scope.AddToVars(condition.values[0]))
+	}
+
+	if !include {
+		return fmt.Sprintf("NOT (%v)", sql)
+	}
+	return fmt.Sprintf("(%v)", sql)
+}
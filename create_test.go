@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jinzhu/now"
+	"gorm.io/gorm"
 )
 
 func TestCreate(t *testing.T) {
@@ -229,3 +230,179 @@ func TestOmitWithCreate(t *testing.T) {
 		t.Errorf("Should not create omitted relationships")
 	}
 }
+
+func TestCreateInBatches(t *testing.T) {
+	products := []Product{
+		{Code: "batch_1", Price: 10},
+		{Code: "batch_2", Price: 20},
+		{Code: "batch_3", Price: 30},
+		{Code: "batch_4", Price: 40},
+		{Code: "batch_5", Price: 50},
+	}
+
+	result := DB.CreateInBatches(&products, 2)
+	if err := result.Error(); err != nil {
+		t.Fatalf("No error should happen when create in batches, but got %v", err)
+	}
+
+	if result.RowsAffected() != 5 {
+		t.Errorf("All records should be created, but got %v rows affected", result.RowsAffected())
+	}
+
+	for _, product := range products {
+		if product.Id == 0 {
+			t.Errorf("Primary key should be set after create in batches")
+		}
+
+		if product.CreatedAt.IsZero() || product.UpdatedAt.IsZero() {
+			t.Errorf("Should set timestamps for every record")
+		}
+
+		if product.BeforeCreateCallTimes != 1 || product.BeforeSaveCallTimes != 1 || product.AfterSaveCallTimes != 1 {
+			t.Errorf("Should run callbacks for every record, but got %v", product)
+		}
+
+		var newProduct Product
+		DB.First(&newProduct, product.Id)
+		if newProduct.Code != product.Code {
+			t.Errorf("Primary key should match the created record, expect %v, but got %v", product.Code, newProduct.Code)
+		}
+
+		if newProduct.AfterCreateCallTimes != 1 {
+			t.Errorf("AfterCreate should be called for every record")
+		}
+	}
+}
+
+func TestCreateSlice(t *testing.T) {
+	users := []*User{{Name: "create_slice_1", Age: 10}, {Name: "create_slice_2", Age: 20}}
+	if err := DB.Create(&users).Error(); err != nil {
+		t.Fatalf("No error should happen when create slice, but got %v", err)
+	}
+
+	var count int
+	DB.Model(&User{}).Where("id IN (?)", []int64{users[0].Id, users[1].Id}).Where("name LIKE ?", "create_slice_%").Count(&count)
+	if count != 2 || users[0].Id == users[1].Id {
+		t.Errorf("Should create all users and set their primary keys, but got %v", count)
+	}
+
+	invalid := []Product{{Code: "batch_valid"}, {Code: "Invalid"}, {Code: "batch_valid_2"}}
+	if err := DB.CreateInBatches(&invalid, 1).Error(); err == nil {
+		t.Errorf("Should return error when a callback failed")
+	}
+
+	if !DB.Where("code = ?", "batch_valid").First(&Product{}).RecordNotFound() {
+		t.Errorf("Should rollback created batches when a batch failed")
+	}
+}
+
+func TestCreateSliceWithDefaultValues(t *testing.T) {
+	animals := []Animal{{Name: "batch_named", From: "batch_default"}, {From: "batch_default"}}
+	if err := DB.Create(&animals).Error(); err != nil {
+		t.Fatalf("No error should happen when create slice, but got %v", err)
+	}
+
+	var named, defaulted Animal
+	DB.First(&named, animals[0].Counter)
+	DB.First(&defaulted, animals[1].Counter)
+	if animals[0].Counter == 0 || animals[1].Counter == 0 || named.Name != "batch_named" || defaulted.Name != "galeone" {
+		t.Errorf("Should use default value for blank columns only, but got %v, %v", named, defaulted)
+	}
+}
+
+type Subscriber struct {
+	Id        int64
+	Email     string `sql:"unique_index"`
+	Name      string
+	Visits    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func TestCreateOnConflict(t *testing.T) {
+	DB.DropTableIfExists(&Subscriber{})
+	if err := DB.AutoMigrate(&Subscriber{}).Error(); err != nil {
+		t.Fatalf("Failed to migrate subscribers, got %v", err)
+	}
+
+	subscriber := Subscriber{Email: "upsert@example.com", Name: "first", Visits: 1}
+	DB.Create(&subscriber)
+
+	updated := Subscriber{Email: "upsert@example.com", Name: "second", Visits: 2}
+	if err := DB.OnConflict(gorm.Conflict{Columns: []string{"email"}, DoUpdates: []string{"name"}}).Create(&updated).Error(); err != nil {
+		t.Fatalf("No error should happen when upsert, but got %v", err)
+	}
+
+	if updated.Id != subscriber.Id {
+		t.Errorf("Should set primary key of the conflicted record, expect %v, but got %v", subscriber.Id, updated.Id)
+	}
+
+	var result Subscriber
+	DB.First(&result, subscriber.Id)
+	if result.Name != "second" || result.Visits != 1 {
+		t.Errorf("Should only update DoUpdates columns, but got %v", result)
+	}
+
+	skipped := Subscriber{Email: "upsert@example.com", Name: "third"}
+	if err := DB.OnConflict(gorm.Conflict{Columns: []string{"email"}, DoNothing: true}).Create(&skipped).Error(); err != nil {
+		t.Errorf("No error should happen when conflicted record is skipped, but got %v", err)
+	}
+
+	subscribers := []Subscriber{{Email: "upsert@example.com", Name: "fourth", Visits: 4}, {Email: "upsert_new@example.com", Name: "new"}}
+	if err := DB.OnConflict(gorm.Conflict{Columns: []string{"Email"}}).CreateInBatches(&subscribers, 10).Error(); err != nil {
+		t.Errorf("No error should happen when upsert in batches, but got %v", err)
+	}
+
+	var count int
+	DB.Model(&Subscriber{}).Count(&count)
+	DB.First(&result, subscriber.Id)
+	if count != 2 || result.Name != "fourth" || result.Visits != 4 {
+		t.Errorf("Should update all inserted columns by default, but got %v records, %v", count, result)
+	}
+
+	if !result.CreatedAt.Equal(subscriber.CreatedAt) {
+		t.Errorf("Should not update created_at by default")
+	}
+
+	if err := DB.Create(&Subscriber{Email: "upsert@example.com"}).Error(); err == nil {
+		t.Errorf("Should return error when conflicted without OnConflict")
+	}
+}
+
+type Member struct {
+	Id    int64
+	Email string `sql:"unique_index"`
+	Name  string
+	Card  MemberCard
+}
+
+type MemberCard struct {
+	Id       int64
+	MemberId int64
+	Number   string
+}
+
+func TestCreateOnConflictWithAssociations(t *testing.T) {
+	DB.DropTableIfExists(&Member{}, &MemberCard{})
+	if err := DB.AutoMigrate(&Member{}, &MemberCard{}).Error(); err != nil {
+		t.Fatalf("Failed to migrate members, got %v", err)
+	}
+
+	member := Member{Email: "member@example.com", Name: "first", Card: MemberCard{Number: "0001"}}
+	DB.Create(&member)
+
+	updated := Member{Email: "member@example.com", Name: "second", Card: MemberCard{Number: "0002"}}
+	if err := DB.OnConflict(gorm.Conflict{Columns: []string{"email"}, DoUpdates: []string{"name"}}).Create(&updated).Error(); err != nil {
+		t.Fatalf("No error should happen when upsert with associations, but got %v", err)
+	}
+
+	if updated.Id != member.Id {
+		t.Errorf("Should set primary key of the conflicted record, expect %v, but got %v", member.Id, updated.Id)
+	}
+
+	var cards []MemberCard
+	DB.Where("member_id = ?", member.Id).Order("id").Find(&cards)
+	if len(cards) != 2 || cards[1].Number != "0002" {
+		t.Errorf("Should save associations of upserted record, but got %v", cards)
+	}
+}
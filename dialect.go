@@ -3,7 +3,9 @@ package gorm
 import (
 	"database/sql"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -18,6 +20,8 @@ type Dialect interface {
 
 	// BindVar return the placeholder for actual values in SQL statements, in many dbs it is "?", Postgres using $1
 	BindVar(i int) string
+	// MaxBindVars return max count of bind vars in a SQL statement, 0 if unlimited
+	MaxBindVars() int
 	// Quote quotes field name to avoid SQL parsing exceptions by using a reserved word as a field name
 	Quote(key string) string
 	// DataTypeOf return data's sql type
@@ -27,14 +31,49 @@ type Dialect interface {
 	HasIndex(tableName string, indexName string) bool
 	// HasForeignKey check has foreign key or not
 	HasForeignKey(tableName string, foreignKeyName string) bool
+	// HasConstraint check has constraint or not, e.g. a check constraint
+	HasConstraint(tableName string, constraintName string) bool
+	// SupportForeignKey check foreign keys could be added to existing tables and be found by `HasForeignKey`
+	SupportForeignKey() bool
+	// SupportAddConstraint check constraints like checks could be added to existing tables with `ALTER TABLE ... ADD CONSTRAINT`
+	SupportAddConstraint() bool
+	// SupportCheckConstraint check constraints are enforced and could be found by `HasConstraint`
+	SupportCheckConstraint() bool
+	// SupportWindowFunction check window functions like `ROW_NUMBER() OVER (PARTITION BY ...)` are supported
+	SupportWindowFunction() bool
 	// RemoveIndex remove index
 	RemoveIndex(tableName string, indexName string) error
+	// CreateIndexSQL return the SQL to create index on table, or an error if options of the index aren't supported; table name and names
+	// of columns which aren't expressions are quoted
+	CreateIndexSQL(tableName string, index Index) (string, error)
 	// HasTable check has table or not
 	HasTable(tableName string) bool
 	// HasColumn check has column or not
 	HasColumn(tableName string, columnName string) bool
 	// ModifyColumn modify column's type
 	ModifyColumn(tableName string, columnName string, typ string) error
+	// RenameColumn rename column of table
+	RenameColumn(tableName string, oldName string, newName string) error
+	// RenameTable rename table
+	RenameTable(oldName string, newName string) error
+	// AlterColumnNullability make column nullable or not, typ is the column's definition without nullability, which is required by mysql, mssql
+	AlterColumnNullability(tableName string, columnName string, typ string, nullable bool) error
+	// Tables return tables of current database
+	Tables() ([]Table, error)
+	// ColumnTypes return columns of table in database, in order of definition
+	ColumnTypes(tableName string) ([]ColumnType, error)
+	// Indexes return indexes of table except the primary key
+	Indexes(tableName string) ([]Index, error)
+	// IsImplicitIndex check index is created by the database for a unique or primary key constraint of columns, e.g. `sqlite_autoindex_users_1`
+	IsImplicitIndex(index Index) bool
+	// ForeignKeys return foreign keys of table
+	ForeignKeys(tableName string) ([]ForeignKey, error)
+	// ColumnCommentSQL return the SQL to comment an existing column, typ is the column's definition without comment; empty if not supported
+	ColumnCommentSQL(tableName string, columnName string, typ string, comment string) string
+	// TableComment return comment of table
+	TableComment(tableName string) string
+	// TableCommentSQL return the SQL to comment a table; table name is quoted; empty if not supported
+	TableCommentSQL(tableName string, comment string) string
 
 	// LimitAndOffsetSQL return generated SQL with Limit and Offset, as mssql has special case
 	LimitAndOffsetSQL(limit, offset interface{}) string
@@ -42,8 +81,16 @@ type Dialect interface {
 	SelectFromDummyTable() string
 	// LastInsertIdReturningSuffix most dbs support LastInsertId, but postgres needs to use `RETURNING`
 	LastInsertIDReturningSuffix(tableName, columnName string) string
+	// BatchLastInsertIDIsFirst return true if LastInsertId of a multi-row INSERT is the id of the first inserted row like mysql, false if it's the last one like sqlite
+	BatchLastInsertIDIsFirst() bool
 	// DefaultValueStr
 	DefaultValueStr() string
+	// SavePointSQL return the SQL to set a savepoint inside current transaction
+	SavePointSQL(name string) string
+	// RollbackToSavePointSQL return the SQL to roll back current transaction to a savepoint
+	RollbackToSavePointSQL(name string) string
+	// UpsertSQL return the SQL to insert rows into table, resolving conflicts as specified by conflict; table name, columns and conflict's columns are quoted
+	UpsertSQL(tableName string, columns []string, rows []string, conflict Conflict) string
 
 	// BuildKeyName returns a valid key name (foreign key, index key) for the given table, field and reference
 	BuildKeyName(kind, tableName string, fields ...string) string
@@ -52,6 +99,68 @@ type Dialect interface {
 	CurrentDatabase() string
 }
 
+// Conflict specify how `Create` resolves rows conflicting with existing records on unique columns, refer `OnConflict`
+type Conflict struct {
+	// Columns unique columns that may conflict, default to primary keys; mysql ignores it and resolves conflicts of any unique key
+	Columns []string
+	// DoUpdates columns updated to inserted values when conflicted, default to all inserted columns except conflict columns, primary keys and `created_at`
+	DoUpdates []string
+	// DoNothing keep conflicted records unchanged
+	DoNothing bool
+}
+
+// Table a table in database, refer `Dialect.Tables`
+type Table struct {
+	Name    string
+	Comment string
+}
+
+// ForeignKey a foreign key constraint of a table, refer `Dialect.ForeignKeys`
+type ForeignKey struct {
+	// Name constraint name, sqlite foreign keys have no name
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	// OnDelete, OnUpdate actions like `CASCADE`, `SET NULL`, `NO ACTION`
+	OnDelete string
+	OnUpdate string
+}
+
+// ColumnType a column of a table in database, refer `Dialect.ColumnTypes`
+type ColumnType struct {
+	Name string
+	// DatabaseType lower case type name without size, e.g. `varchar`, `integer`
+	DatabaseType string
+	// Size length of character and binary types, 0 if unknown or unlimited
+	Size     int
+	Nullable bool
+	// Default default value expression, invalid if the column has no default value
+	Default    sql.NullString
+	Comment    string
+	PrimaryKey bool
+}
+
+// Index an index of a table, refer `CreateIndex`
+type Index struct {
+	Name    string        `json:"name"`
+	Unique  bool          `json:"unique,omitempty"`
+	Columns []IndexColumn `json:"columns"`
+	// Type index method or kind, e.g. `gin` for postgres, `FULLTEXT`, `HASH` for mysql, `CLUSTERED` for mssql, ignored by sqlite
+	Type string `json:"type,omitempty"`
+	// Where predicate of a partial index, mysql doesn't support it
+	Where string `json:"where,omitempty"`
+}
+
+// IndexColumn a column or an expression of an index
+type IndexColumn struct {
+	// Name column name, or expression like `lower(email)` if Expression is true, an expression inspected from mysql or sqlite has no name
+	Name       string `json:"name"`
+	Expression bool   `json:"expression,omitempty"`
+	// Sort `ASC` or `DESC`
+	Sort string `json:"sort,omitempty"`
+}
+
 var dialectsMap = map[string]Dialect{}
 
 func newDialect(name string, db SQLCommon) Dialect {
@@ -127,6 +236,25 @@ var ParseFieldStructForDialect = func(field *StructField, dialect Dialect) (fiel
 	return fieldValue, dataType, size, strings.TrimSpace(additionalType)
 }
 
+var versionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// versionAtLeast check version like `8.0.33` or `10.5.8-MariaDB` is at least major.minor, or major.minor.patch if patch given
+func versionAtLeast(version string, major, minor int, patch ...int) bool {
+	matches := versionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return false
+	}
+
+	expected := append([]int{major, minor}, patch...)
+	for idx, number := range expected {
+		current, _ := strconv.Atoi(matches[idx+1])
+		if current != number {
+			return current > number
+		}
+	}
+	return true
+}
+
 func currentDatabaseAndTable(dialect Dialect, tableName string) (string, string) {
 	if strings.Contains(tableName, ".") {
 		splitStrings := strings.SplitN(tableName, ".", 2)
@@ -134,3 +262,161 @@ func currentDatabaseAndTable(dialect Dialect, tableName string) (string, string)
 	}
 	return dialect.CurrentDatabase(), tableName
 }
+
+// nullabilitySQL return `NULL` or `NOT NULL` for column definitions
+func nullabilitySQL(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// scanTables scan rows of table name and comment
+func scanTables(rows *sql.Rows) ([]Table, error) {
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var (
+			table   Table
+			comment sql.NullString
+		)
+		if err := rows.Scan(&table.Name, &comment); err != nil {
+			return nil, err
+		}
+		table.Comment = comment.String
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// scanColumnTypes scan rows of column name, data type, max length, is nullable (`YES`/`NO`), default value, comment and is primary key
+func scanColumnTypes(rows *sql.Rows) ([]ColumnType, error) {
+	defer rows.Close()
+
+	var columnTypes []ColumnType
+	for rows.Next() {
+		var (
+			columnType ColumnType
+			size       sql.NullInt64
+			nullable   string
+			comment    sql.NullString
+			primaryKey sql.NullBool
+		)
+		if err := rows.Scan(&columnType.Name, &columnType.DatabaseType, &size, &nullable, &columnType.Default, &comment, &primaryKey); err != nil {
+			return nil, err
+		}
+		columnType.Comment, columnType.PrimaryKey = comment.String, primaryKey.Bool
+
+		columnType.DatabaseType = strings.ToLower(columnType.DatabaseType)
+		if size.Valid && size.Int64 > 0 && size.Int64 < math.MaxInt32 {
+			columnType.Size = int(size.Int64)
+		}
+		columnType.Nullable = strings.ToUpper(nullable) == "YES"
+		columnTypes = append(columnTypes, columnType)
+	}
+	return columnTypes, rows.Err()
+}
+
+// splitColumnType split a column definition like `varchar(255) NOT NULL` to lower case type name and size, modifiers are discarded
+func splitColumnType(typ string) (name string, size int) {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(typ)) {
+		if columnTypeModifiers[strings.SplitN(word, "(", 2)[0]] {
+			break
+		}
+		words = append(words, word)
+	}
+	name = strings.Join(words, " ")
+
+	if start := strings.Index(name, "("); start >= 0 {
+		if end := strings.Index(name, ")"); end > start {
+			size, _ = strconv.Atoi(strings.TrimSpace(strings.SplitN(name[start+1:end], ",", 2)[0]))
+			name = strings.TrimSpace(name[:start] + " " + strings.TrimSpace(name[end+1:]))
+		}
+	}
+	return
+}
+
+var columnTypeModifiers = map[string]bool{
+	"not": true, "null": true, "default": true, "unique": true, "primary": true, "references": true, "check": true, "comment": true,
+	"unsigned": true, "auto_increment": true, "autoincrement": true, "identity": true,
+}
+
+// indexColumnsSQL join columns of index with their sort orders, mysql requires expressions to be enclosed in parentheses
+func indexColumnsSQL(index Index, enclosesExpression bool) string {
+	var columns []string
+	for _, column := range index.Columns {
+		name := column.Name
+		if column.Expression && enclosesExpression {
+			name = "(" + name + ")"
+		}
+		columns = append(columns, strings.TrimSpace(name+" "+column.Sort))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// ScanIndexes scan rows of index name, is unique, type, predicate, column name or expression, is expression and is descending, which are
+// ordered by index name and position of columns, to indexes; it is used by dialects to implement `Indexes`
+func ScanIndexes(rows *sql.Rows) ([]Index, error) {
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		var (
+			name, typ, where, column                    string
+			nullableType, nullableWhere, nullableColumn sql.NullString
+			unique, expression, descending              bool
+		)
+		if err := rows.Scan(&name, &unique, &nullableType, &nullableWhere, &nullableColumn, &expression, &descending); err != nil {
+			return nil, err
+		}
+		typ, where, column = nullableType.String, nullableWhere.String, nullableColumn.String
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			indexes = append(indexes, Index{Name: name, Unique: unique, Type: typ, Where: where})
+		}
+
+		indexColumn := IndexColumn{Name: column, Expression: expression || !nullableColumn.Valid}
+		if descending {
+			indexColumn.Sort = "DESC"
+		}
+
+		index := &indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, indexColumn)
+	}
+	return indexes, rows.Err()
+}
+
+// ScanForeignKeys scan rows of constraint name, column, referenced table, referenced column, delete action and update action, which are
+// ordered by constraint name and position of columns, to foreign keys; it is used by dialects to implement `ForeignKeys`
+func ScanForeignKeys(rows *sql.Rows) ([]ForeignKey, error) {
+	defer rows.Close()
+
+	var foreignKeys []ForeignKey
+	for rows.Next() {
+		var name, column, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &column, &referencedTable, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+
+		if len(foreignKeys) == 0 || foreignKeys[len(foreignKeys)-1].Name != name {
+			foreignKeys = append(foreignKeys, ForeignKey{
+				Name:            name,
+				ReferencedTable: referencedTable,
+				OnDelete:        normalizeReferentialAction(onDelete),
+				OnUpdate:        normalizeReferentialAction(onUpdate),
+			})
+		}
+
+		foreignKey := &foreignKeys[len(foreignKeys)-1]
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
+	}
+	return foreignKeys, rows.Err()
+}
+
+// normalizeReferentialAction normalize actions like `NO_ACTION`, `set null` to `NO ACTION`, `SET NULL`
+func normalizeReferentialAction(action string) string {
+	return strings.Replace(strings.ToUpper(strings.TrimSpace(action)), "_", " ", -1)
+}
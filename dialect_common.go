@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,6 +17,8 @@ type DefaultForeignKeyNamer struct {
 type commonDialect struct {
 	db SQLCommon
 	DefaultForeignKeyNamer
+	// probed capabilities of the connection, e.g. window functions, which are queried once
+	probed *sync.Map
 }
 
 func init() {
@@ -28,6 +31,21 @@ func (commonDialect) GetName() string {
 
 func (s *commonDialect) SetDB(db SQLCommon) {
 	s.db = db
+	s.probed = &sync.Map{}
+}
+
+// probe return the cached capability of the connection, fc is called to query it at the first time
+func (s commonDialect) probe(name string, fc func() bool) bool {
+	if s.probed == nil {
+		return fc()
+	}
+
+	if value, ok := s.probed.Load(name); ok {
+		return value.(bool)
+	}
+	value := fc()
+	s.probed.Store(name, value)
+	return value
 }
 
 func (commonDialect) BindVar(i int) string {
@@ -109,10 +127,50 @@ func (s commonDialect) RemoveIndex(tableName string, indexName string) error {
 	return err
 }
 
+func (commonDialect) CreateIndexSQL(tableName string, index Index) (string, error) {
+	sql := "CREATE INDEX"
+	if index.Unique {
+		sql = "CREATE UNIQUE INDEX"
+	}
+
+	sql = fmt.Sprintf("%v %v ON %v(%v)", sql, index.Name, tableName, indexColumnsSQL(index, false))
+	if index.Where != "" {
+		sql += " WHERE " + index.Where
+	}
+	return sql, nil
+}
+
 func (s commonDialect) HasForeignKey(tableName string, foreignKeyName string) bool {
 	return false
 }
 
+func (s commonDialect) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE table_schema = ? AND table_name = ? AND constraint_name = ?", currentDatabase, tableName, constraintName).Scan(&count)
+	return count > 0
+}
+
+func (commonDialect) SupportForeignKey() bool {
+	return false
+}
+
+func (commonDialect) SupportAddConstraint() bool {
+	return true
+}
+
+func (commonDialect) SupportCheckConstraint() bool {
+	return true
+}
+
+func (commonDialect) SupportWindowFunction() bool {
+	return false
+}
+
+func (commonDialect) MaxBindVars() int {
+	return 0
+}
+
 func (s commonDialect) HasTable(tableName string) bool {
 	var count int
 	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
@@ -132,6 +190,77 @@ func (s commonDialect) ModifyColumn(tableName string, columnName string, typ str
 	return err
 }
 
+func (s commonDialect) RenameColumn(tableName string, oldName string, newName string) error {
+	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v RENAME COLUMN %v TO %v", tableName, oldName, newName))
+	return err
+}
+
+func (s commonDialect) RenameTable(oldName string, newName string) error {
+	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v RENAME TO %v", oldName, newName))
+	return err
+}
+
+func (s commonDialect) AlterColumnNullability(tableName string, columnName string, typ string, nullable bool) error {
+	action := "SET NOT NULL"
+	if nullable {
+		action = "DROP NOT NULL"
+	}
+	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v %v", tableName, columnName, action))
+	return err
+}
+
+func (s commonDialect) Tables() ([]Table, error) {
+	rows, err := s.db.Query("SELECT table_name, NULL FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE' ORDER BY table_name", s.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (s commonDialect) ColumnTypes(tableName string) ([]ColumnType, error) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	rows, err := s.db.Query("SELECT column_name, data_type, character_maximum_length, is_nullable, column_default, NULL, NULL FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position", currentDatabase, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumnTypes(rows)
+}
+
+// Indexes return indexes of table from `INFORMATION_SCHEMA.STATISTICS`, collation is NULL for FULLTEXT, SPATIAL and HASH indexes
+func (s commonDialect) Indexes(tableName string) ([]Index, error) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	rows, err := s.db.Query("SELECT index_name, non_unique = 0, index_type, NULL, column_name, column_name IS NULL, COALESCE(collation = 'D', 0) FROM INFORMATION_SCHEMA.STATISTICS WHERE table_schema = ? AND table_name = ? AND index_name <> 'PRIMARY' ORDER BY index_name, seq_in_index", currentDatabase, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return ScanIndexes(rows)
+}
+
+func (commonDialect) IsImplicitIndex(index Index) bool {
+	return false
+}
+
+func (s commonDialect) ForeignKeys(tableName string) ([]ForeignKey, error) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	rows, err := s.db.Query("SELECT k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name, r.delete_rule, r.update_rule FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE k JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS r ON r.constraint_schema = k.constraint_schema AND r.constraint_name = k.constraint_name AND r.table_name = k.table_name WHERE k.table_schema = ? AND k.table_name = ? AND k.referenced_table_name IS NOT NULL ORDER BY k.constraint_name, k.ordinal_position", currentDatabase, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return ScanForeignKeys(rows)
+}
+
+func (commonDialect) ColumnCommentSQL(tableName string, columnName string, typ string, comment string) string {
+	return ""
+}
+
+func (commonDialect) TableComment(tableName string) string {
+	return ""
+}
+
+func (commonDialect) TableCommentSQL(tableName string, comment string) string {
+	return ""
+}
+
 func (s commonDialect) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT DATABASE()").Scan(&name)
 	return
@@ -159,10 +288,35 @@ func (commonDialect) LastInsertIDReturningSuffix(tableName, columnName string) s
 	return ""
 }
 
+func (commonDialect) BatchLastInsertIDIsFirst() bool {
+	return true
+}
+
 func (commonDialect) DefaultValueStr() string {
 	return "DEFAULT VALUES"
 }
 
+func (commonDialect) SavePointSQL(name string) string {
+	return fmt.Sprintf("SAVEPOINT %v", name)
+}
+
+func (commonDialect) RollbackToSavePointSQL(name string) string {
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %v", name)
+}
+
+func (commonDialect) UpsertSQL(tableName string, columns []string, rows []string, conflict Conflict) string {
+	sql := fmt.Sprintf("INSERT INTO %v (%v) VALUES %v ON CONFLICT (%v)", tableName, strings.Join(columns, ","), strings.Join(rows, ","), strings.Join(conflict.Columns, ","))
+	if conflict.DoNothing {
+		return sql + " DO NOTHING"
+	}
+
+	var assignments []string
+	for _, column := range conflict.DoUpdates {
+		assignments = append(assignments, fmt.Sprintf("%v = excluded.%v", column, column))
+	}
+	return sql + " DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
 // BuildKeyName returns a valid key name (foreign key, index key) for the given table, field and reference
 func (DefaultForeignKeyNamer) BuildKeyName(kind, tableName string, fields ...string) string {
 	keyName := fmt.Sprintf("%s_%s_%s", kind, tableName, strings.Join(fields, "_"))
@@ -121,6 +121,10 @@ func (s *mysql) DataTypeOf(field *StructField) string {
 		panic(fmt.Sprintf("invalid sql type %s (%s) for mysql", dataValue.Type().Name(), dataValue.Kind().String()))
 	}
 
+	if comment, ok := field.TagSettings["COMMENT"]; ok {
+		additionalType = strings.TrimSpace(additionalType + " COMMENT " + quoteSQLString("mysql", comment))
+	}
+
 	if strings.TrimSpace(additionalType) == "" {
 		return sqlType
 	}
@@ -132,11 +136,94 @@ func (s mysql) RemoveIndex(tableName string, indexName string) error {
 	return err
 }
 
+// CreateIndexSQL return the SQL to create index, mysql doesn't support partial indexes, or unique FULLTEXT and SPATIAL indexes
+func (mysql) CreateIndexSQL(tableName string, index Index) (string, error) {
+	if index.Where != "" {
+		return "", fmt.Errorf("gorm: partial indexes are not supported by mysql, index %v has where condition %v", index.Name, index.Where)
+	}
+
+	var kind, using string
+	if index.Unique {
+		kind = "UNIQUE "
+	}
+
+	switch typ := strings.ToUpper(index.Type); typ {
+	case "FULLTEXT", "SPATIAL":
+		if index.Unique {
+			return "", fmt.Errorf("gorm: %v index %v can't be unique in mysql", typ, index.Name)
+		}
+		kind = typ + " "
+	case "":
+	default:
+		using = " USING " + typ
+	}
+
+	return fmt.Sprintf("CREATE %vINDEX %v ON %v(%v)%v", kind, index.Name, tableName, indexColumnsSQL(index, true), using), nil
+}
+
 func (s mysql) ModifyColumn(tableName string, columnName string, typ string) error {
 	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v MODIFY COLUMN %v %v", tableName, columnName, typ))
 	return err
 }
 
+// RenameColumn change the column with its current definition read from `SHOW CREATE TABLE`, as `RENAME COLUMN` requires MySQL 8.0, MariaDB 10.5.2
+func (s mysql) RenameColumn(tableName string, oldName string, newName string) error {
+	var name, createSQL string
+	if err := s.db.QueryRow(fmt.Sprintf("SHOW CREATE TABLE %v", tableName)).Scan(&name, &createSQL); err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(createSQL, "\n") {
+		if definition := strings.TrimSpace(line); strings.HasPrefix(definition, oldName+" ") {
+			definition = strings.TrimSuffix(strings.TrimPrefix(definition, oldName+" "), ",")
+			_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v CHANGE COLUMN %v %v %v", tableName, oldName, newName, definition))
+			return err
+		}
+	}
+	return fmt.Errorf("gorm: column %v not found in table %v", oldName, tableName)
+}
+
+// AlterColumnNullability modify the column with its definition, which is required by mysql
+func (s mysql) AlterColumnNullability(tableName string, columnName string, typ string, nullable bool) error {
+	if typ == "" {
+		return fmt.Errorf("gorm: definition of column %v is required to change its nullability in mysql", columnName)
+	}
+	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v MODIFY COLUMN %v %v %v", tableName, columnName, typ, nullabilitySQL(nullable)))
+	return err
+}
+
+func (s mysql) Tables() ([]Table, error) {
+	rows, err := s.db.Query("SELECT table_name, table_comment FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE' ORDER BY table_name", s.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (s mysql) ColumnTypes(tableName string) ([]ColumnType, error) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	// `boolean` is an alias of `tinyint(1)` in mysql
+	rows, err := s.db.Query("SELECT column_name, CASE WHEN column_type = 'tinyint(1)' THEN 'boolean' ELSE data_type END, character_maximum_length, is_nullable, column_default, column_comment, column_key = 'PRI' FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position", currentDatabase, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumnTypes(rows)
+}
+
+func (mysql) ColumnCommentSQL(tableName string, columnName string, typ string, comment string) string {
+	return fmt.Sprintf("ALTER TABLE %v MODIFY COLUMN %v %v COMMENT %v", tableName, columnName, typ, quoteSQLString("mysql", comment))
+}
+
+func (s mysql) TableComment(tableName string) (comment string) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	s.db.QueryRow("SELECT table_comment FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?", currentDatabase, tableName).Scan(&comment)
+	return
+}
+
+func (mysql) TableCommentSQL(tableName string, comment string) string {
+	return fmt.Sprintf("ALTER TABLE %v COMMENT = %v", tableName, quoteSQLString("mysql", comment))
+}
+
 func (s mysql) LimitAndOffsetSQL(limit, offset interface{}) (sql string) {
 	if limit != nil {
 		if parsedLimit, err := strconv.ParseInt(fmt.Sprint(limit), 0, 0); err == nil && parsedLimit >= 0 {
@@ -159,6 +246,43 @@ func (s mysql) HasForeignKey(tableName string, foreignKeyName string) bool {
 	return count > 0
 }
 
+func (mysql) SupportForeignKey() bool {
+	return true
+}
+
+func (mysql) MaxBindVars() int {
+	return 65535
+}
+
+// SupportWindowFunction window functions are supported since MySQL 8.0 and MariaDB 10.2, the version is queried once
+func (s mysql) SupportWindowFunction() bool {
+	return s.probe("window_function", func() bool {
+		var version string
+		s.db.QueryRow("SELECT VERSION()").Scan(&version)
+		if strings.Contains(strings.ToLower(version), "mariadb") {
+			return versionAtLeast(version, 10, 2)
+		}
+		return versionAtLeast(version, 8, 0)
+	})
+}
+
+// SupportCheckConstraint check constraints are parsed but ignored before MySQL 8.0.16 and MariaDB 10.2.1, the version is queried once
+func (s mysql) SupportCheckConstraint() bool {
+	return s.probe("check_constraint", func() bool {
+		var version string
+		s.db.QueryRow("SELECT VERSION()").Scan(&version)
+		if strings.Contains(strings.ToLower(version), "mariadb") {
+			return versionAtLeast(version, 10, 2, 1)
+		}
+		return versionAtLeast(version, 8, 0, 16)
+	})
+}
+
+// IsImplicitIndex check the index is created for a `UNIQUE` column, which mysql names after the column
+func (mysql) IsImplicitIndex(index Index) bool {
+	return index.Unique && len(index.Columns) == 1 && index.Name == index.Columns[0].Name
+}
+
 func (s mysql) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT DATABASE()").Scan(&name)
 	return
@@ -168,6 +292,23 @@ func (mysql) SelectFromDummyTable() string {
 	return "FROM DUAL"
 }
 
+func (mysql) UpsertSQL(tableName string, columns []string, rows []string, conflict Conflict) string {
+	var assignments []string
+	if conflict.DoNothing {
+		// assign a column to itself as mysql has no `DO NOTHING`, `INSERT IGNORE` would ignore other errors too
+		column := columns[0]
+		if len(conflict.Columns) > 0 {
+			column = conflict.Columns[0]
+		}
+		assignments = append(assignments, fmt.Sprintf("%v = %v", column, column))
+	} else {
+		for _, column := range conflict.DoUpdates {
+			assignments = append(assignments, fmt.Sprintf("%v = VALUES(%v)", column, column))
+		}
+	}
+	return fmt.Sprintf("INSERT INTO %v (%v) VALUES %v ON DUPLICATE KEY UPDATE %v", tableName, strings.Join(columns, ","), strings.Join(rows, ","), strings.Join(assignments, ", "))
+}
+
 func (s mysql) BuildKeyName(kind, tableName string, fields ...string) string {
 	keyName := s.commonDialect.BuildKeyName(kind, tableName, fields...)
 	if utf8.RuneCountInString(keyName) <= 64 {
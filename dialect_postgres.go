@@ -1,9 +1,11 @@
 package gorm
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -97,12 +99,48 @@ func (s postgres) HasIndex(tableName string, indexName string) bool {
 	return count > 0
 }
 
+func (postgres) CreateIndexSQL(tableName string, index Index) (string, error) {
+	sql := "CREATE INDEX"
+	if index.Unique {
+		sql = "CREATE UNIQUE INDEX"
+	}
+
+	sql = fmt.Sprintf("%v %v ON %v", sql, index.Name, tableName)
+	if index.Type != "" {
+		sql += " USING " + index.Type
+	}
+
+	sql += fmt.Sprintf("(%v)", indexColumnsSQL(index, false))
+	if index.Where != "" {
+		sql += " WHERE " + index.Where
+	}
+	return sql, nil
+}
+
 func (s postgres) HasForeignKey(tableName string, foreignKeyName string) bool {
 	var count int
 	s.db.QueryRow("SELECT count(con.conname) FROM pg_constraint con WHERE $1::regclass::oid = con.conrelid AND con.conname = $2 AND con.contype='f'", tableName, foreignKeyName).Scan(&count)
 	return count > 0
 }
 
+func (s postgres) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.table_constraints WHERE table_name = $1 AND constraint_name = $2 AND table_schema = CURRENT_SCHEMA()", tableName, constraintName).Scan(&count)
+	return count > 0
+}
+
+func (postgres) SupportForeignKey() bool {
+	return true
+}
+
+func (postgres) SupportWindowFunction() bool {
+	return true
+}
+
+func (postgres) MaxBindVars() int {
+	return 65535
+}
+
 func (s postgres) HasTable(tableName string) bool {
 	var count int
 	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1 AND table_type = 'BASE TABLE' AND table_schema = CURRENT_SCHEMA()", tableName).Scan(&count)
@@ -115,6 +153,96 @@ func (s postgres) HasColumn(tableName string, columnName string) bool {
 	return count > 0
 }
 
+// ModifyColumn change column's type, a trailing `NOT NULL`/`NULL` and `DEFAULT` of typ are applied as separated alterations
+func (s postgres) ModifyColumn(tableName string, columnName string, typ string) error {
+	matches := columnDefinitionRegexp.FindStringSubmatch(strings.TrimSpace(typ))
+	if matches == nil {
+		return s.commonDialect.ModifyColumn(tableName, columnName, typ)
+	}
+
+	alterations := []string{fmt.Sprintf("ALTER COLUMN %v TYPE %v", columnName, matches[1])}
+	switch strings.ToUpper(strings.TrimSpace(matches[2])) {
+	case "NOT NULL":
+		alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %v SET NOT NULL", columnName))
+	case "NULL":
+		alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %v DROP NOT NULL", columnName))
+	}
+	if matches[3] != "" {
+		alterations = append(alterations, fmt.Sprintf("ALTER COLUMN %v SET DEFAULT %v", columnName, matches[3]))
+	}
+
+	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v %v", tableName, strings.Join(alterations, ", ")))
+	return err
+}
+
+func (s postgres) Tables() ([]Table, error) {
+	rows, err := s.db.Query("SELECT c.relname, obj_description(c.oid, 'pg_class') FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relkind IN ('r', 'p') AND n.nspname = CURRENT_SCHEMA() ORDER BY c.relname")
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (s postgres) ColumnTypes(tableName string) ([]ColumnType, error) {
+	rows, err := s.db.Query(`SELECT column_name, CASE WHEN data_type = 'USER-DEFINED' THEN udt_name ELSE data_type END, character_maximum_length, is_nullable, column_default, col_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, ordinal_position),
+		EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.table_constraints tc JOIN INFORMATION_SCHEMA.key_column_usage kcu ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name AND kcu.column_name = c.column_name)
+		FROM INFORMATION_SCHEMA.columns c WHERE table_name = $1 AND table_schema = CURRENT_SCHEMA() ORDER BY ordinal_position`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumnTypes(rows)
+}
+
+func (s postgres) Indexes(tableName string) ([]Index, error) {
+	rows, err := s.db.Query(`SELECT i.relname, ix.indisunique, am.amname, pg_get_expr(ix.indpred, ix.indrelid), pg_get_indexdef(ix.indexrelid, k.n, true), ix.indkey[k.n - 1] = 0, ix.indoption[k.n - 1] & 1 = 1
+		FROM pg_class t JOIN pg_namespace ns ON ns.oid = t.relnamespace JOIN pg_index ix ON ix.indrelid = t.oid JOIN pg_class i ON i.oid = ix.indexrelid JOIN pg_am am ON am.oid = i.relam
+		CROSS JOIN generate_series(1, ix.indnatts) AS k(n)
+		WHERE t.relname = $1 AND ns.nspname = CURRENT_SCHEMA() AND NOT ix.indisprimary ORDER BY i.relname, k.n`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return ScanIndexes(rows)
+}
+
+// IsImplicitIndex check the index backs a unique constraint, e.g. `users_email_key` of a `UNIQUE` column
+func (s postgres) IsImplicitIndex(index Index) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM pg_constraint con JOIN pg_namespace ns ON ns.oid = con.connamespace WHERE con.conname = $1 AND con.contype = 'u' AND ns.nspname = CURRENT_SCHEMA()", index.Name).Scan(&count)
+	return count > 0
+}
+
+func (s postgres) ForeignKeys(tableName string) ([]ForeignKey, error) {
+	rows, err := s.db.Query(`SELECT con.conname, a.attname, ft.relname, fa.attname, `+postgresReferentialActionSQL("con.confdeltype")+`, `+postgresReferentialActionSQL("con.confupdtype")+`
+		FROM pg_constraint con JOIN pg_class t ON t.oid = con.conrelid JOIN pg_namespace ns ON ns.oid = t.relnamespace JOIN pg_class ft ON ft.oid = con.confrelid
+		CROSS JOIN generate_subscripts(con.conkey, 1) AS k(i)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = con.conkey[k.i] JOIN pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = con.confkey[k.i]
+		WHERE con.contype = 'f' AND t.relname = $1 AND ns.nspname = CURRENT_SCHEMA() ORDER BY con.conname, k.i`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return ScanForeignKeys(rows)
+}
+
+// postgresReferentialActionSQL convert action codes of pg_constraint to names
+func postgresReferentialActionSQL(column string) string {
+	return fmt.Sprintf("CASE %v WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END", column)
+}
+
+func (postgres) ColumnCommentSQL(tableName string, columnName string, typ string, comment string) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %v.%v IS %v", tableName, columnName, quoteSQLString("postgres", comment))
+}
+
+func (s postgres) TableComment(tableName string) string {
+	var comment sql.NullString
+	s.db.QueryRow("SELECT obj_description(c.oid, 'pg_class') FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = $1 AND n.nspname = CURRENT_SCHEMA()", tableName).Scan(&comment)
+	return comment.String
+}
+
+func (postgres) TableCommentSQL(tableName string, comment string) string {
+	return fmt.Sprintf("COMMENT ON TABLE %v IS %v", tableName, quoteSQLString("postgres", comment))
+}
+
 func (s postgres) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT CURRENT_DATABASE()").Scan(&name)
 	return
@@ -128,6 +256,9 @@ func (postgres) SupportLastInsertID() bool {
 	return false
 }
 
+// columnDefinitionRegexp match a column definition like `varchar(255) NOT NULL DEFAULT 'a'` as type, nullability and default value
+var columnDefinitionRegexp = regexp.MustCompile(`(?i)^(.+?)(\s+NOT NULL|\s+NULL)?(?:\s+DEFAULT\s+(.+))?$`)
+
 func isUUID(value reflect.Value) bool {
 	if value.Kind() != reflect.Array || value.Type().Len() != 16 {
 		return false
@@ -1,8 +1,13 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -88,6 +93,217 @@ func (s sqlite3) HasColumn(tableName string, columnName string) bool {
 	return count > 0
 }
 
+func (s sqlite3) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow(fmt.Sprintf("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND tbl_name = ? AND (sql LIKE '%%CONSTRAINT \"%v\" %%' OR sql LIKE '%%CONSTRAINT %v %%')", constraintName, constraintName), tableName).Scan(&count)
+	return count > 0
+}
+
+// ModifyColumn change column's definition by rebuilding the table, as sqlite can't alter columns
+func (s sqlite3) ModifyColumn(tableName string, columnName string, typ string) error {
+	return s.rebuildTable(tableName, columnName, func(definition string) string {
+		return s.Quote(unquoteSqlite(columnName)) + " " + typ
+	})
+}
+
+// AlterColumnNullability add or remove `NOT NULL` of column by rebuilding the table, as sqlite can't alter columns
+func (s sqlite3) AlterColumnNullability(tableName string, columnName string, typ string, nullable bool) error {
+	return s.rebuildTable(tableName, columnName, func(definition string) string {
+		definition = sqliteNotNullRegexp.ReplaceAllString(definition, "")
+		if !nullable {
+			definition += " NOT NULL"
+		}
+		return definition
+	})
+}
+
+func (s sqlite3) Tables() ([]Table, error) {
+	rows, err := s.db.Query("SELECT name, NULL FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (s sqlite3) ColumnTypes(tableName string) ([]ColumnType, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columnTypes []ColumnType
+	for rows.Next() {
+		var (
+			columnType ColumnType
+			cid, pk    int
+			typ        string
+			notNull    bool
+		)
+		if err := rows.Scan(&cid, &columnType.Name, &typ, &notNull, &columnType.Default, &pk); err != nil {
+			return nil, err
+		}
+
+		columnType.DatabaseType, columnType.Size = splitColumnType(typ)
+		columnType.Nullable, columnType.PrimaryKey = !notNull, pk > 0
+		columnTypes = append(columnTypes, columnType)
+	}
+	return columnTypes, rows.Err()
+}
+
+func (s sqlite3) Indexes(tableName string) ([]Index, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA index_list(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		indexes  []Index
+		partials = map[string]bool{}
+	)
+	for rows.Next() {
+		var (
+			index   Index
+			seq     int
+			origin  string
+			partial bool
+		)
+		if err := rows.Scan(&seq, &index.Name, &index.Unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if origin != "pk" {
+			indexes = append(indexes, index)
+			partials[index.Name] = partial
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Columns, err = s.indexColumns(index.Name); err != nil {
+			return nil, err
+		}
+
+		if partials[index.Name] {
+			var createSQL string
+			if err := s.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", index.Name).Scan(&createSQL); err != nil {
+				return nil, err
+			}
+
+			if idx := strings.LastIndex(strings.ToUpper(createSQL), " WHERE "); idx >= 0 {
+				index.Where = strings.TrimSpace(createSQL[idx+len(" WHERE "):])
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// indexColumns return key columns of index, expressions have no name
+func (s sqlite3) indexColumns(indexName string) ([]IndexColumn, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA index_xinfo(%v)", s.Quote(indexName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []IndexColumn
+	for rows.Next() {
+		var (
+			seqNo, cid      int
+			name, collation sql.NullString
+			desc, key       bool
+		)
+		if err := rows.Scan(&seqNo, &cid, &name, &desc, &collation, &key); err != nil {
+			return nil, err
+		}
+
+		if key {
+			column := IndexColumn{Name: name.String, Expression: cid == -2}
+			if desc {
+				column.Sort = "DESC"
+			}
+			columns = append(columns, column)
+		}
+	}
+	return columns, rows.Err()
+}
+
+func (s sqlite3) ForeignKeys(tableName string) ([]ForeignKey, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		foreignKeys []ForeignKey
+		positions   = map[int]int{}
+	)
+	for rows.Next() {
+		var (
+			id, seq                                int
+			table, from, onUpdate, onDelete, match string
+			to                                     sql.NullString
+		)
+		if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+
+		position, ok := positions[id]
+		if !ok {
+			position = len(foreignKeys)
+			positions[id] = position
+			foreignKeys = append(foreignKeys, ForeignKey{
+				ReferencedTable: table,
+				OnDelete:        normalizeReferentialAction(onDelete),
+				OnUpdate:        normalizeReferentialAction(onUpdate),
+			})
+		}
+
+		foreignKey := &foreignKeys[position]
+		foreignKey.Columns = append(foreignKey.Columns, from)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, to.String)
+	}
+	return foreignKeys, rows.Err()
+}
+
+// MaxBindVars SQLITE_MAX_VARIABLE_NUMBER defaults to 999 before sqlite 3.32
+func (sqlite3) MaxBindVars() int {
+	return 999
+}
+
+// IsImplicitIndex sqlite names indexes of unique and primary key constraints like `sqlite_autoindex_<table>_<n>`
+func (sqlite3) IsImplicitIndex(index Index) bool {
+	return strings.HasPrefix(index.Name, "sqlite_autoindex_")
+}
+
+// SupportAddConstraint sqlite can't add constraints to existing tables
+func (sqlite3) SupportAddConstraint() bool {
+	return false
+}
+
+func (sqlite3) BatchLastInsertIDIsFirst() bool {
+	return false
+}
+
+// SupportWindowFunction window functions are supported since sqlite 3.25, the version is queried once
+func (s sqlite3) SupportWindowFunction() bool {
+	return s.probe("window_function", func() bool {
+		var version string
+		s.db.QueryRow("SELECT sqlite_version()").Scan(&version)
+		return versionAtLeast(version, 3, 25)
+	})
+}
+
 func (s sqlite3) CurrentDatabase() (name string) {
 	var (
 		ifaces   = make([]interface{}, 3)
@@ -105,3 +321,172 @@ func (s sqlite3) CurrentDatabase() (name string) {
 	}
 	return
 }
+
+var sqliteNotNullRegexp = regexp.MustCompile(`(?i)\s+NOT\s+NULL\b`)
+
+// rebuildTable change definition of column with the create-copy-drop-rename procedure documented in https://www.sqlite.org/lang_altertable.html,
+// indexes and triggers of the table are recreated
+func (s sqlite3) rebuildTable(tableName string, columnName string, alter func(definition string) string) error {
+	tableName, columnName = unquoteSqlite(tableName), unquoteSqlite(columnName)
+
+	var createSQL string
+	if err := s.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&createSQL); err != nil {
+		return fmt.Errorf("gorm: failed to find definition of table %v, got %v", tableName, err)
+	}
+
+	start, end := strings.Index(createSQL, "("), strings.LastIndex(createSQL, ")")
+	if start < 0 || end < start {
+		return fmt.Errorf("gorm: failed to parse definition of table %v", tableName)
+	}
+
+	var (
+		definitions = splitSqliteDefinitions(createSQL[start+1 : end])
+		found       bool
+	)
+	for idx, definition := range definitions {
+		if fields := strings.Fields(definition); len(fields) > 0 && strings.EqualFold(unquoteSqlite(fields[0]), columnName) {
+			definitions[idx], found = alter(definition), true
+		}
+	}
+	if !found {
+		return fmt.Errorf("gorm: column %v not found in table %v", columnName, tableName)
+	}
+
+	columnTypes, err := s.ColumnTypes(tableName)
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	for _, columnType := range columnTypes {
+		columns = append(columns, s.Quote(columnType.Name))
+	}
+
+	rows, err := s.db.Query("SELECT sql FROM sqlite_master WHERE tbl_name = ? AND type IN ('index', 'trigger') AND sql IS NOT NULL", tableName)
+	if err != nil {
+		return err
+	}
+
+	var schemaSQLs []string
+	for rows.Next() {
+		var schemaSQL string
+		if err := rows.Scan(&schemaSQL); err != nil {
+			rows.Close()
+			return err
+		}
+		schemaSQLs = append(schemaSQLs, schemaSQL)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var (
+		tempTableName = s.Quote(tableName + "__rebuild")
+		quotedColumns = strings.Join(columns, ",")
+		statements    = []string{
+			fmt.Sprintf("CREATE TABLE %v (%v)%v", tempTableName, strings.Join(definitions, ","), createSQL[end+1:]),
+			fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v", tempTableName, quotedColumns, quotedColumns, s.Quote(tableName)),
+			fmt.Sprintf("DROP TABLE %v", s.Quote(tableName)),
+			fmt.Sprintf("ALTER TABLE %v RENAME TO %v", tempTableName, s.Quote(tableName)),
+		}
+	)
+	return s.execInTransaction(append(statements, schemaSQLs...))
+}
+
+// execInTransaction exec statements in a transaction with foreign keys disabled, if db is a transaction already, statements run in it
+// and checks of foreign keys are deferred to its commit, as foreign keys can't be disabled inside a transaction
+func (s sqlite3) execInTransaction(statements []string) error {
+	db, ok := s.db.(*sql.DB)
+	if !ok {
+		for _, statement := range append([]string{"PRAGMA defer_foreign_keys = ON"}, statements...) {
+			if _, err := s.db.Exec(statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// pragmas are per connection, so they should be run in the same connection with the transaction
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var foreignKeys bool
+	if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		return err
+	}
+
+	if foreignKeys {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if foreignKeys {
+		rows, err := tx.Query("PRAGMA foreign_key_check")
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		violated := rows.Next()
+		rows.Close()
+		if violated {
+			tx.Rollback()
+			return errors.New("gorm: foreign key constraints are violated after rebuilding table")
+		}
+	}
+	return tx.Commit()
+}
+
+// splitSqliteDefinitions split definitions of columns and constraints in `CREATE TABLE` by commas out of parentheses and quotes
+func splitSqliteDefinitions(sql string) (definitions []string) {
+	var (
+		depth int
+		quote byte
+		start int
+	)
+
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			definitions = append(definitions, strings.TrimSpace(sql[start:i]))
+			start = i + 1
+		}
+	}
+	return append(definitions, strings.TrimSpace(sql[start:]))
+}
+
+// unquoteSqlite remove quotes of an identifier, e.g. `"users"`, `[users]`
+func unquoteSqlite(name string) string {
+	return strings.Trim(name, "\"`[]")
+}
@@ -1,11 +1,13 @@
 package mssql
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -133,6 +135,28 @@ func (s mssql) RemoveIndex(tableName string, indexName string) error {
 	return err
 }
 
+func (mssql) CreateIndexSQL(tableName string, index gorm.Index) (string, error) {
+	var columns []string
+	for _, column := range index.Columns {
+		columns = append(columns, strings.TrimSpace(column.Name+" "+column.Sort))
+	}
+
+	sql := "CREATE "
+	if index.Unique {
+		sql += "UNIQUE "
+	}
+	if index.Type != "" {
+		// CLUSTERED or NONCLUSTERED
+		sql += strings.ToUpper(index.Type) + " "
+	}
+
+	sql += fmt.Sprintf("INDEX %v ON %v(%v)", index.Name, tableName, strings.Join(columns, ", "))
+	if index.Where != "" {
+		sql += " WHERE " + index.Where
+	}
+	return sql, nil
+}
+
 func (s mssql) HasForeignKey(tableName string, foreignKeyName string) bool {
 	var count int
 	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
@@ -144,6 +168,33 @@ func (s mssql) HasForeignKey(tableName string, foreignKeyName string) bool {
 	return count > 0
 }
 
+func (s mssql) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE table_catalog = ? AND table_name = ? AND constraint_name = ?", currentDatabase, tableName, constraintName).Scan(&count)
+	return count > 0
+}
+
+func (mssql) SupportForeignKey() bool {
+	return true
+}
+
+func (mssql) SupportAddConstraint() bool {
+	return true
+}
+
+func (mssql) SupportCheckConstraint() bool {
+	return true
+}
+
+func (mssql) SupportWindowFunction() bool {
+	return true
+}
+
+func (mssql) MaxBindVars() int {
+	return 2100
+}
+
 func (s mssql) HasTable(tableName string) bool {
 	var count int
 	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
@@ -159,10 +210,127 @@ func (s mssql) HasColumn(tableName string, columnName string) bool {
 }
 
 func (s mssql) ModifyColumn(tableName string, columnName string, typ string) error {
+	// defaults are constraints in mssql, they can't be changed by `ALTER COLUMN` and remain unchanged
+	typ = columnDefaultRegexp.ReplaceAllString(typ, "")
 	_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v %v", tableName, columnName, typ))
 	return err
 }
 
+// RenameColumn rename column with `sp_rename`, which requires names without quotes
+func (s mssql) RenameColumn(tableName string, oldName string, newName string) error {
+	_, err := s.db.Exec("EXEC sp_rename ?, ?, 'COLUMN'", unquote(tableName)+"."+unquote(oldName), unquote(newName))
+	return err
+}
+
+// RenameTable rename table with `sp_rename`, which requires names without quotes
+func (s mssql) RenameTable(oldName string, newName string) error {
+	_, err := s.db.Exec("EXEC sp_rename ?, ?", unquote(oldName), unquote(newName))
+	return err
+}
+
+func (s mssql) AlterColumnNullability(tableName string, columnName string, typ string, nullable bool) error {
+	if typ == "" {
+		return fmt.Errorf("gorm: definition of column %v is required to change its nullability in mssql", columnName)
+	}
+
+	nullability := "NOT NULL"
+	if nullable {
+		nullability = "NULL"
+	}
+	return s.ModifyColumn(tableName, columnName, columnDefaultRegexp.ReplaceAllString(typ, "")+" "+nullability)
+}
+
+func (s mssql) Tables() ([]gorm.Table, error) {
+	rows, err := s.db.Query("SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_catalog = ? AND table_type = 'BASE TABLE' ORDER BY table_name", s.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []gorm.Table
+	for rows.Next() {
+		var table gorm.Table
+		if err := rows.Scan(&table.Name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (s mssql) ColumnTypes(tableName string) ([]gorm.ColumnType, error) {
+	currentDatabase, tableName := currentDatabaseAndTable(&s, tableName)
+	rows, err := s.db.Query(`SELECT column_name, data_type, character_maximum_length, is_nullable, column_default,
+		CASE WHEN EXISTS (SELECT 1 FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu ON kcu.constraint_catalog = tc.constraint_catalog AND kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_catalog = c.table_catalog AND tc.table_name = c.table_name AND kcu.column_name = c.column_name) THEN 1 ELSE 0 END
+		FROM information_schema.columns c WHERE table_catalog = ? AND table_name = ? ORDER BY ordinal_position`, currentDatabase, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columnTypes []gorm.ColumnType
+	for rows.Next() {
+		var (
+			columnType gorm.ColumnType
+			size       sql.NullInt64
+			nullable   string
+		)
+		if err := rows.Scan(&columnType.Name, &columnType.DatabaseType, &size, &nullable, &columnType.Default, &columnType.PrimaryKey); err != nil {
+			return nil, err
+		}
+
+		columnType.DatabaseType = strings.ToLower(columnType.DatabaseType)
+		if size.Int64 > 0 {
+			columnType.Size = int(size.Int64)
+		}
+		columnType.Nullable = nullable == "YES"
+		columnTypes = append(columnTypes, columnType)
+	}
+	return columnTypes, rows.Err()
+}
+
+func (s mssql) Indexes(tableName string) ([]gorm.Index, error) {
+	rows, err := s.db.Query(`SELECT i.name, i.is_unique, i.type_desc, i.filter_definition, c.name, CAST(0 AS bit), ic.is_descending_key
+		FROM sys.indexes i JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(?) AND i.is_primary_key = 0 AND ic.is_included_column = 0 ORDER BY i.name, ic.key_ordinal`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return gorm.ScanIndexes(rows)
+}
+
+// IsImplicitIndex check the index backs a unique constraint with a generated name like `UQ__users__AB6E6164`
+func (mssql) IsImplicitIndex(index gorm.Index) bool {
+	return strings.HasPrefix(index.Name, "UQ__")
+}
+
+func (s mssql) ForeignKeys(tableName string) ([]gorm.ForeignKey, error) {
+	rows, err := s.db.Query(`SELECT f.name, pc.name, rt.name, rc.name, f.delete_referential_action_desc, f.update_referential_action_desc
+		FROM sys.foreign_keys f JOIN sys.foreign_key_columns fc ON fc.constraint_object_id = f.object_id
+		JOIN sys.columns pc ON pc.object_id = fc.parent_object_id AND pc.column_id = fc.parent_column_id
+		JOIN sys.tables rt ON rt.object_id = fc.referenced_object_id JOIN sys.columns rc ON rc.object_id = fc.referenced_object_id AND rc.column_id = fc.referenced_column_id
+		WHERE f.parent_object_id = OBJECT_ID(?) ORDER BY f.name, fc.constraint_column_id`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return gorm.ScanForeignKeys(rows)
+}
+
+// ColumnCommentSQL return empty as comments are extended properties in mssql, which are not supported
+func (mssql) ColumnCommentSQL(tableName string, columnName string, typ string, comment string) string {
+	return ""
+}
+
+func (mssql) TableComment(tableName string) string {
+	return ""
+}
+
+// TableCommentSQL return empty as comments are extended properties in mssql, which are not supported
+func (mssql) TableCommentSQL(tableName string, comment string) string {
+	return ""
+}
+
 func (s mssql) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT DB_NAME() AS [Current Database]").Scan(&name)
 	return
@@ -194,10 +362,43 @@ func (mssql) LastInsertIDReturningSuffix(tableName, columnName string) string {
 	return ""
 }
 
+func (mssql) BatchLastInsertIDIsFirst() bool {
+	return true
+}
+
 func (mssql) DefaultValueStr() string {
 	return "DEFAULT VALUES"
 }
 
+func (mssql) SavePointSQL(name string) string {
+	return fmt.Sprintf("SAVE TRANSACTION %v", name)
+}
+
+func (mssql) RollbackToSavePointSQL(name string) string {
+	return fmt.Sprintf("ROLLBACK TRANSACTION %v", name)
+}
+
+func (mssql) UpsertSQL(tableName string, columns []string, rows []string, conflict gorm.Conflict) string {
+	var conditions, assignments, values []string
+	for _, column := range conflict.Columns {
+		conditions = append(conditions, fmt.Sprintf("%v.%v = excluded.%v", tableName, column, column))
+	}
+	for _, column := range conflict.DoUpdates {
+		assignments = append(assignments, fmt.Sprintf("%v = excluded.%v", column, column))
+	}
+	for _, column := range columns {
+		values = append(values, "excluded."+column)
+	}
+
+	sql := fmt.Sprintf("MERGE INTO %v USING (VALUES %v) AS excluded (%v) ON %v", tableName, strings.Join(rows, ","), strings.Join(columns, ","), strings.Join(conditions, " AND "))
+	if !conflict.DoNothing {
+		sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(assignments, ", ")
+	}
+	return sql + fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%v) VALUES (%v);", strings.Join(columns, ","), strings.Join(values, ","))
+}
+
+var columnDefaultRegexp = regexp.MustCompile(`(?i)\s+DEFAULT\s+.+$`)
+
 func currentDatabaseAndTable(dialect gorm.Dialect, tableName string) (string, string) {
 	if strings.Contains(tableName, ".") {
 		splitStrings := strings.SplitN(tableName, ".", 2)
@@ -229,3 +430,8 @@ func (j *JSON) Scan(value interface{}) error {
 	bytes := []byte(str)
 	return json.Unmarshal(bytes, j)
 }
+
+// unquote remove brackets of a name quoted by `Quote`, e.g. `[dbo].[users]` to `dbo.users`
+func unquote(name string) string {
+	return strings.NewReplacer("[", "", "]", "").Replace(name)
+}
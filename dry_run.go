@@ -0,0 +1,191 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// Statement a statement built but not executed in dry run mode
+type Statement struct {
+	SQL  string
+	Vars []interface{}
+	// Explained SQL with vars rendered, for reading only, refer `Scope.ExplainSQL`
+	Explained string
+}
+
+// dryRunRecorder collect statements built in dry run mode, it is shared by all dbs and scopes created from the dry run db
+type dryRunRecorder struct {
+	mu      sync.Mutex
+	dialect Dialect
+	db      SQLCommon
+	// inspect run queries of dialects against db instead of recording them, so the real schema is inspected, refer `MigrationPlan`
+	inspect    bool
+	statements []Statement
+}
+
+func (recorder *dryRunRecorder) record(dialect Dialect, sql string, vars []interface{}) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.statements = append(recorder.statements, Statement{
+		SQL:       sql,
+		Vars:      append([]interface{}{}, vars...),
+		Explained: explainSQL(dialect, sql, vars...),
+	})
+}
+
+func (recorder *dryRunRecorder) recorded() []Statement {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return append([]Statement{}, recorder.statements...)
+}
+
+// isDryRun return true if statements are built but not executed
+func (scope *Scope) isDryRun() bool {
+	_, ok := scope.Get("gorm:dry_run")
+	return ok
+}
+
+// recordDryRun record scope's SQL if running in dry run mode, returns true if so, then the SQL shouldn't be executed
+func (scope *Scope) recordDryRun() bool {
+	value, ok := scope.Get("gorm:dry_run")
+	if !ok {
+		return false
+	}
+
+	if recorder, ok := value.(*dryRunRecorder); ok {
+		recorder.record(scope.Dialect(), scope.SQL, scope.SQLVars)
+	}
+	return true
+}
+
+// dryRunDB replace the connection in dry run mode, so statements run by dialects, e.g. `HasTable`, `ModifyColumn`, are recorded
+// instead of executed; queries return no rows, and execs affect no rows
+type dryRunDB struct {
+	recorder *dryRunRecorder
+}
+
+func (db dryRunDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	db.recorder.record(db.recorder.dialect, query, args)
+	return dryRunResult{}, nil
+}
+
+func (db dryRunDB) Prepare(query string) (*sql.Stmt, error) {
+	db.recorder.record(db.recorder.dialect, query, nil)
+	return emptyDB().Prepare(query)
+}
+
+func (db dryRunDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if db.recorder.inspect {
+		return db.recorder.db.Query(query, args...)
+	}
+	db.recorder.record(db.recorder.dialect, query, args)
+	return emptyDB().Query(query)
+}
+
+func (db dryRunDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	if db.recorder.inspect {
+		return db.recorder.db.QueryRow(query, args...)
+	}
+	db.recorder.record(db.recorder.dialect, query, args)
+	return emptyDB().QueryRow(query)
+}
+
+type dryRunResult struct{}
+
+func (dryRunResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (dryRunResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+var (
+	emptyDBOnce sync.Once
+	emptySQLDB  *sql.DB
+)
+
+// emptyDB return a db of which every query returns no rows, it is used to build `*sql.Row`, `*sql.Rows` in dry run mode
+func emptyDB() *sql.DB {
+	emptyDBOnce.Do(func() {
+		emptySQLDB = sql.OpenDB(emptyConnector{})
+	})
+	return emptySQLDB
+}
+
+type emptyConnector struct{}
+
+func (emptyConnector) Connect(context.Context) (driver.Conn, error) {
+	return emptyConn{}, nil
+}
+
+func (emptyConnector) Driver() driver.Driver {
+	return emptyDriver{}
+}
+
+type emptyDriver struct{}
+
+func (emptyDriver) Open(name string) (driver.Conn, error) {
+	return emptyConn{}, nil
+}
+
+type emptyConn struct{}
+
+func (emptyConn) Prepare(query string) (driver.Stmt, error) {
+	return emptyStmt{}, nil
+}
+
+func (emptyConn) Close() error {
+	return nil
+}
+
+func (emptyConn) Begin() (driver.Tx, error) {
+	return emptyTx{}, nil
+}
+
+type emptyTx struct{}
+
+func (emptyTx) Commit() error {
+	return nil
+}
+
+func (emptyTx) Rollback() error {
+	return nil
+}
+
+type emptyStmt struct{}
+
+func (emptyStmt) Close() error {
+	return nil
+}
+
+func (emptyStmt) NumInput() int {
+	return -1
+}
+
+func (emptyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return dryRunResult{}, nil
+}
+
+func (emptyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string {
+	return nil
+}
+
+func (emptyRows) Close() error {
+	return nil
+}
+
+func (emptyRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
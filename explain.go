@@ -0,0 +1,152 @@
+package gorm
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ExplainSQL return scope's SQL with vars rendered as literals of its dialect, for logging and debugging only, never run it
+func (scope *Scope) ExplainSQL() string {
+	return explainSQL(scope.Dialect(), scope.SQL, scope.SQLVars...)
+}
+
+// explainSQL replace bind vars `?`, `$n`, `@pn` out of quoted literals and identifiers with vars, dialect could be nil
+func explainSQL(dialect Dialect, sql string, vars ...interface{}) string {
+	if len(vars) == 0 {
+		return sql
+	}
+
+	var (
+		result     strings.Builder
+		quote      byte
+		varIndex   int
+		escapeable = dialect != nil && dialect.GetName() == "mysql"
+	)
+
+	writeVar := func(idx int, placeholder string) {
+		if idx >= 0 && idx < len(vars) {
+			result.WriteString(explainValue(dialect, vars[idx]))
+		} else {
+			result.WriteString(placeholder)
+		}
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			result.WriteByte(c)
+			if c == '\\' && escapeable && quote == '\'' && i+1 < len(sql) {
+				i++
+				result.WriteByte(sql[i])
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			result.WriteByte(c)
+		case c == '?':
+			writeVar(varIndex, "?")
+			varIndex++
+		case c == '$' || c == '@':
+			start := i + 1
+			if c == '@' && start < len(sql) && (sql[start] == 'p' || sql[start] == 'P') {
+				start++
+			}
+
+			end := start
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+
+			if end == start || (c == '@' && start == i+1) {
+				result.WriteByte(c)
+				continue
+			}
+
+			var position int
+			fmt.Sscan(sql[start:end], &position)
+			writeVar(position-1, sql[i:end])
+			i = end - 1
+		default:
+			result.WriteByte(c)
+		}
+	}
+	return result.String()
+}
+
+// explainValue render value as a SQL literal of dialect
+func explainValue(dialect Dialect, value interface{}) string {
+	var name string
+	if dialect != nil {
+		name = dialect.GetName()
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return "NULL"
+		}
+		if valuer, ok := reflectValue.Interface().(driver.Valuer); ok {
+			value = valuer
+			break
+		}
+		reflectValue = reflectValue.Elem()
+		value = reflectValue.Interface()
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		result, err := valuer.Value()
+		if err != nil || result == nil {
+			return "NULL"
+		}
+		return explainValue(dialect, result)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteSQLString(name, v)
+	case time.Time:
+		return quoteSQLString(name, v.Format("2006-01-02 15:04:05.999999"))
+	case bool:
+		if name == "postgres" {
+			return strings.ToUpper(fmt.Sprint(v))
+		} else if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		if str := string(v); isPrintable(str) {
+			return quoteSQLString(name, str)
+		}
+
+		switch name {
+		case "postgres":
+			return fmt.Sprintf(`'\x%v'`, hex.EncodeToString(v))
+		case "mssql":
+			return "0x" + hex.EncodeToString(v)
+		}
+		return fmt.Sprintf("X'%v'", hex.EncodeToString(v))
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(value)
+	}
+	return quoteSQLString(name, fmt.Sprint(value))
+}
+
+func quoteSQLString(dialectName, str string) string {
+	if dialectName == "mysql" {
+		str = strings.Replace(str, `\`, `\\`, -1)
+	}
+	return "'" + strings.Replace(str, "'", "''", -1) + "'"
+}
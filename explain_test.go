@@ -0,0 +1,30 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainSQL(t *testing.T) {
+	now := time.Date(2020, 2, 23, 11, 10, 10, 0, time.UTC)
+	name := "jinzhu"
+
+	cases := []struct {
+		dialect  Dialect
+		sql      string
+		vars     []interface{}
+		expected string
+	}{
+		{&sqlite3{}, "SELECT * FROM users WHERE name = ? AND age > ? AND note <> '?'", []interface{}{"it's", 18}, "SELECT * FROM users WHERE name = 'it''s' AND age > 18 AND note <> '?'"},
+		{&mysql{}, "SELECT * FROM `users?` WHERE name = ? AND note = 'a\\'?' AND active = ?", []interface{}{`c:\dir`, true}, "SELECT * FROM `users?` WHERE name = 'c:\\\\dir' AND note = 'a\\'?' AND active = 1"},
+		{&postgres{}, `SELECT * FROM "users" WHERE name = $1 AND created_at < $2 AND active = $3 AND id IN ($10)`, []interface{}{&name, now, false}, `SELECT * FROM "users" WHERE name = 'jinzhu' AND created_at < '2020-02-23 11:10:10' AND active = FALSE AND id IN ($10)`},
+		{&postgres{}, `INSERT INTO "files" ("data","deleted_at") VALUES ($1,$2)`, []interface{}{[]byte{0, 1}, (*time.Time)(nil)}, `INSERT INTO "files" ("data","deleted_at") VALUES ('\x0001',NULL)`},
+		{nil, "SELECT * FROM users WHERE name = @p1 AND email = @p2 AND age = @age", []interface{}{"jinzhu", "jinzhu@example.org"}, "SELECT * FROM users WHERE name = 'jinzhu' AND email = 'jinzhu@example.org' AND age = @age"},
+	}
+
+	for _, c := range cases {
+		if result := explainSQL(c.dialect, c.sql, c.vars...); result != c.expected {
+			t.Errorf("Explained SQL should be\n%v\nbut got\n%v", c.expected, result)
+		}
+	}
+}
@@ -15,11 +15,11 @@
 package gorm
 
 import (
+	"context"
 	"database/sql"
 	"time"
 	"fmt"
 	"errors"
-	"github.com/jinzhu/copier"
 )
 
 type Mocker interface {
@@ -36,22 +36,24 @@ type FakeRepository struct {
 	// single db
 	db                SQLCommon
 	blockGlobalUpdate bool
-	logMode           int
-	logger            Logger
+	logLevel          LogLevel
+	logger            ContextLogger
 	search            *Search
 	values            map[string]interface{}
+	ctx               context.Context
 
 	// global db
 	parent        Repository
 	callbacks     *Callback
 	dialect       Dialect
 	singularTable bool
-	mockData      map[string]interface{}
+	state         *fakeState
+	chain         int
 }
 
 // New clone a new db connection without search conditions
 func (r *FakeRepository) New() Repository {
-	clone := r.Clone()
+	clone := r.record("New").Clone()
 	clone.SetSearch(nil)
 	clone.SetValue(nil)
 	return clone
@@ -90,18 +92,35 @@ func (r *FakeRepository) Callback() *Callback {
 	return r.parent.Callbacks()
 }
 
-// SetLogger replace default logger
+// SetLogger replace default logger, it is adapted to a ContextLogger by `NewLegacyLogger`
 func (r *FakeRepository) SetLogger(log Logger) Repository {
-	r.logger = log
+	r.logger = NewLegacyLogger(log, DefaultLoggerConfig)
+	if r.logLevel != 0 {
+		r.logger = r.logger.LogMode(r.logLevel)
+	}
+	return r
+}
+
+// SetContextLogger replace default logger with a leveled logger
+//     db.SetContextLogger(gorm.NewJSONLogger(os.Stdout, gorm.LoggerConfig{SlowThreshold: time.Second, LogLevel: gorm.LogWarn}))
+func (r *FakeRepository) SetContextLogger(logger ContextLogger) Repository {
+	r.logger = logger
 	return r
 }
 
-// LogMode set log mode, `true` for detailed logs, `false` for no log, default, will only print error logs
+// ContextLogger return current leveled logger
+func (r *FakeRepository) ContextLogger() ContextLogger {
+	return r.logger
+}
+
+// LogMode set log mode, `true` for all logs, `false` for errors and slow SQL only, default
 func (r *FakeRepository) LogMode(enable bool) Repository {
+	r.logLevel = LogWarn
 	if enable {
-		r.logMode = 2
-	} else {
-		r.logMode = 1
+		r.logLevel = LogInfo
+	}
+	if r.logger != nil {
+		r.logger = r.logger.LogMode(r.logLevel)
 	}
 	return r
 }
@@ -151,27 +170,27 @@ func (r *FakeRepository) SubQuery() *Expression {
 
 // Where return a new relation, filter records with given conditions, accepts `map`, `struct` or `string` as conditions, refer http://jinzhu.github.io/gorm/crud.html#query
 func (r *FakeRepository) Where(query interface{}, args ...interface{}) Repository {
-	return r
+	return r.record("Where", query, args).Clone().Search().Where(query, args...).db
 }
 
 // Or filter records that match before conditions or this one, similar to `Where`
 func (r *FakeRepository) Or(query interface{}, args ...interface{}) Repository {
-	return r
+	return r.record("Or", query, args).Clone().Search().Or(query, args...).db
 }
 
 // Not filter records that don't match current conditions, similar to `Where`
 func (r *FakeRepository) Not(query interface{}, args ...interface{}) Repository {
-	return r
+	return r.record("Not", query, args).Clone().Search().Not(query, args...).db
 }
 
 // Limit specify the number of records to be retrieved
 func (r *FakeRepository) Limit(limit interface{}) Repository {
-	return r
+	return r.record("Limit", limit).Clone().Search().Limit(limit).db
 }
 
 // Offset specify the number of records to skip before starting to return the records
 func (r *FakeRepository) Offset(offset interface{}) Repository {
-	return r
+	return r.record("Offset", offset).Clone().Search().Offset(offset).db
 }
 
 // Order specify order when retrieve records from database, set reorder to `true` to overwrite defined conditions
@@ -179,97 +198,117 @@ func (r *FakeRepository) Offset(offset interface{}) Repository {
 //     db.Order("name DESC", true) // reorder
 //     db.Order(gorm.Expr("name = ? DESC", "first")) // sql expression
 func (r *FakeRepository) Order(value interface{}, reorder ...bool) Repository {
-	return r
+	return r.record("Order", value, reorder).Clone().Search().Order(value, reorder...).db
 }
 
 // Select specify fields that you want to retrieve from database when querying, by default, will select all fields;
 // When creating/updating, specify fields that you want to save to database
 func (r *FakeRepository) Select(query interface{}, args ...interface{}) Repository {
-	return r
+	return r.record("Select", query, args).Clone().Search().Select(query, args...).db
 }
 
 // Omit specify fields that you want to ignore when saving to database for creating, updating
 func (r *FakeRepository) Omit(columns ...string) Repository {
-	return r
+	return r.record("Omit", columns).Clone().Search().Omit(columns...).db
+}
+
+// OnConflict specify how to resolve conflicts with existing records when creating
+func (r *FakeRepository) OnConflict(conflict Conflict) Repository {
+	clone := r.record("OnConflict", conflict).Clone()
+	clone.Values()["gorm:on_conflict"] = conflict
+	return clone
 }
 
 // Group specify the group method on the find
 func (r *FakeRepository) Group(query string) Repository {
-	return r
+	return r.record("Group", query).Clone().Search().Group(query).db
 }
 
 // Having specify HAVING conditions for GROUP BY
 func (r *FakeRepository) Having(query interface{}, values ...interface{}) Repository {
-	return r
+	return r.record("Having", query, values).Clone().Search().Having(query, values...).db
 }
 
 // Joins specify Joins conditions
 //     db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
 func (r *FakeRepository) Joins(query string, args ...interface{}) Repository {
-	return r
+	return r.record("Joins", query, args).Clone().Search().Joins(query, args...).db
+}
+
+// InnerJoins join the table of a relationship with `INNER JOIN`
+func (r *FakeRepository) InnerJoins(relationship string, args ...interface{}) Repository {
+	return r.record("InnerJoins", relationship, args).Clone().Search().relationshipJoins("INNER JOIN", relationship, args...).db
+}
+
+// LeftJoins join the table of a relationship with `LEFT JOIN`
+func (r *FakeRepository) LeftJoins(relationship string, args ...interface{}) Repository {
+	return r.record("LeftJoins", relationship, args).Clone().Search().relationshipJoins("LEFT JOIN", relationship, args...).db
 }
 
 func (r *FakeRepository) Scopes(funcs ...func(Repository) Repository) Repository {
-	return r
+	var db Repository
+	db = r.record("Scopes", funcs)
+	for _, fn := range funcs {
+		db = fn(db)
+	}
+	return db
 }
 
 // Unscoped return all record including deleted record, refer Soft Delete https://jinzhu.github.io/gorm/crud.html#soft-delete
 func (r *FakeRepository) Unscoped() Repository {
-	return r
+	return r.record("Unscoped").Clone().Search().unscoped().db
 }
 
 // Attrs initialize struct with argument if record not found with `FirstOrInit` https://jinzhu.github.io/gorm/crud.html#firstorinit or `FirstOrCreate` https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (r *FakeRepository) Attrs(attrs ...interface{}) Repository {
-	return r
+	return r.record("Attrs", attrs).Clone().Search().Attrs(attrs...).db
 }
 
 // Assign assign result with argument regardless it is found or not with `FirstOrInit` https://jinzhu.github.io/gorm/crud.html#firstorinit or `FirstOrCreate` https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (r *FakeRepository) Assign(attrs ...interface{}) Repository {
-	return r
+	return r.record("Assign", attrs).Clone().Search().Assign(attrs...).db
 }
 
 // First find first record that match given conditions, order by primary key
 func (r *FakeRepository) First(out interface{}, where ...interface{}) Repository {
-	r.copyData("First", out)
-	return r
+	return r.record("First", out, where).call(fakeQuery, "First", out, where...)
 }
 
 // Take return a record that match given conditions, the order will depend on the database implementation
 func (r *FakeRepository) Take(out interface{}, where ...interface{}) Repository {
-	r.copyData("Take", out)
-	return r
+	return r.record("Take", out, where).call(fakeQuery, "Take", out, where...)
 }
 
 // Last find last record that match given conditions, order by primary key
 func (r *FakeRepository) Last(out interface{}, where ...interface{}) Repository {
-	r.copyData("Last", out)
-	return r
+	return r.record("Last", out, where).call(fakeQuery, "Last", out, where...)
 }
 
 // Find find records that match given conditions
 func (r *FakeRepository) Find(out interface{}, where ...interface{}) Repository {
-	r.copyData("Find", out)
-	return r
+	return r.record("Find", out, where).call(fakeQuery, "Find", out, where...)
 }
 
 // Scan scan value to a struct
 func (r *FakeRepository) Scan(dest interface{}) Repository {
-	r.copyData("Scan", dest)
-	return r
+	return r.record("Scan", dest).call(fakeQuery, "Scan", dest)
 }
 
 // Row return `*sql.Row` with given conditions
 func (r *FakeRepository) Row() *sql.Row {
+	r.record("Row")
 	return nil
 }
 
 // Rows return `*sql.Rows` with given conditions
 func (r *FakeRepository) Rows() (*sql.Rows, error) {
+	r.record("Rows")
 	return nil, nil
 }
 
 // ScanRows scan `*sql.Rows` to give struct
 func (r *FakeRepository) ScanRows(rows *sql.Rows, result interface{}) error {
+	r.record("ScanRows", rows, result)
 	return nil
 }
 
@@ -277,77 +316,80 @@ func (r *FakeRepository) ScanRows(rows *sql.Rows, result interface{}) error {
 //     var ages []int64
 //     db.Find(&users).Pluck("age", &ages)
 func (r *FakeRepository) Pluck(column string, value interface{}) Repository {
-	return r
+	return r.record("Pluck", column, value).call(fakeQuery, "Pluck", value)
 }
 
 // Count get how many records for a model
 func (r *FakeRepository) Count(value interface{}) Repository {
-	return r
+	return r.record("Count", value).call(fakeQuery, "Count", value)
 }
 
 // Related get related associations
 func (r *FakeRepository) Related(value interface{}, foreignKeys ...string) Repository {
-	return r
+	return r.record("Related", value, foreignKeys)
 }
 
 // FirstOrInit find first matched record or initialize a new one with given conditions (only works with struct, map conditions)
 // https://jinzhu.github.io/gorm/crud.html#firstorinit
 func (r *FakeRepository) FirstOrInit(out interface{}, where ...interface{}) Repository {
-	r.copyData("FirstOrInit", out)
-	return r
+	return r.record("FirstOrInit", out, where).call(fakeQuery, "FirstOrInit", out, where...)
 }
 
 // FirstOrCreate find first matched record or create a new one with given conditions (only works with struct, map conditions)
 // https://jinzhu.github.io/gorm/crud.html#firstorcreate
 func (r *FakeRepository) FirstOrCreate(out interface{}, where ...interface{}) Repository {
-	r.copyData("FirstOrCreate", out)
-	return r
+	return r.record("FirstOrCreate", out, where).call(fakeQuery, "FirstOrCreate", out, where...)
 }
 
 // Update update attributes with callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (r *FakeRepository) Update(attrs ...interface{}) Repository {
-	return r
+	return r.record("Update", attrs).callUpdate("Update", toSearchableMap(attrs...))
 }
 
 // Updates update attributes with callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (r *FakeRepository) Updates(values interface{}, ignoreProtectedAttrs ...bool) Repository {
-	return r
+	return r.record("Updates", values, ignoreProtectedAttrs).callUpdate("Updates", values)
 }
 
 // UpdateColumn update attributes without callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (r *FakeRepository) UpdateColumn(attrs ...interface{}) Repository {
-	return r
+	return r.record("UpdateColumn", attrs).callUpdate("UpdateColumn", toSearchableMap(attrs...))
 }
 
 // UpdateColumns update attributes without callbacks, refer: https://jinzhu.github.io/gorm/crud.html#update
 func (r *FakeRepository) UpdateColumns(values interface{}) Repository {
-	return r
+	return r.record("UpdateColumns", values).callUpdate("UpdateColumns", values)
 }
 
 // Save update value in database, if the value doesn't have primary key, will insert it
 func (r *FakeRepository) Save(value interface{}) Repository {
-	return r
+	return r.record("Save", value).call(fakeSave, "Save", value)
 }
 
 // Create insert the value into database
 func (r *FakeRepository) Create(value interface{}) Repository {
-	return r
+	return r.record("Create", value).call(fakeCreate, "Create", value)
+}
+
+// CreateInBatches insert a slice of values with multi-row INSERT statements, each containing at most batchSize rows
+func (r *FakeRepository) CreateInBatches(values interface{}, batchSize int) Repository {
+	return r.record("CreateInBatches", values, batchSize).call(fakeCreate, "CreateInBatches", values)
 }
 
 // Delete delete value match given conditions, if the value has primary key, then will including the primary key as condition
 func (r *FakeRepository) Delete(value interface{}, where ...interface{}) Repository {
-	return r
+	return r.record("Delete", value, where).call(fakeDelete, "Delete", value, where...)
 }
 
 // Raw use raw sql as conditions, won't run it unless invoked by other methods
 //    db.Raw("SELECT name, age FROM users WHERE name = ?", 3).Scan(&result)
 func (r *FakeRepository) Raw(sql string, values ...interface{}) Repository {
-	return r
+	return r.record("Raw", sql, values).Clone().Search().Raw(true).Where(sql, values...).db
 }
 
 // Exec execute raw sql
 func (r *FakeRepository) Exec(sql string, values ...interface{}) Repository {
-	return r
+	return r.record("Exec", sql, values)
 }
 
 // Model specify the model you would like to run db operations
@@ -356,36 +398,70 @@ func (r *FakeRepository) Exec(sql string, values ...interface{}) Repository {
 //    // if user's primary key is non-blank, will use it as condition, then will only update the user's name to `hello`
 //    db.Model(&user).Update("name", "hello")
 func (r *FakeRepository) Model(value interface{}) Repository {
-	return r
+	c := r.record("Model", value).Clone()
+	c.SetValue(value)
+	return c
 }
 
 // Table specify the table you would like to run db operations
 func (r *FakeRepository) Table(name string) Repository {
-	return r
+	clone := r.record("Table", name).Clone()
+	clone.Search().Table(name)
+	clone.SetValue(nil)
+	return clone
+}
+
+// WithContext return a new relation bound to ctx, it could be read back with `Context`
+func (r *FakeRepository) WithContext(ctx context.Context) Repository {
+	c := r.record("WithContext", ctx).Clone().(*FakeRepository)
+	c.ctx = ctx
+	return c
 }
 
 // Debug start debug mode
 func (r *FakeRepository) Debug() Repository {
-	return r
+	return r.record("Debug").Clone().LogMode(true)
 }
 
 // Begin begin a transaction
 func (r *FakeRepository) Begin() Repository {
-	return r
+	return r.record("Begin")
 }
 
 // Commit commit a transaction
 func (r *FakeRepository) Commit() Repository {
-	return r
+	return r.record("Commit")
 }
 
 // Rollback rollback a transaction
 func (r *FakeRepository) Rollback() Repository {
-	return r
+	return r.record("Rollback")
+}
+
+// SavePoint set a savepoint with name inside current transaction
+func (r *FakeRepository) SavePoint(name string) Repository {
+	return r.record("SavePoint", name)
+}
+
+// RollbackTo rollback current transaction to the savepoint with name
+func (r *FakeRepository) RollbackTo(name string) Repository {
+	return r.record("RollbackTo", name)
+}
+
+// ToSQL run fc with current fake repository, returns empty string as FakeRepository doesn't build SQL
+func (r *FakeRepository) ToSQL(fc func(tx Repository) Repository) string {
+	fc(r.record("ToSQL", fc))
+	return ""
+}
+
+// Transaction run fc with current fake repository, calls made by fc are recorded in the same chain
+func (r *FakeRepository) Transaction(fc func(tx Repository) error) error {
+	return fc(r.record("Transaction", fc))
 }
 
 // NewRecord check if value's primary key is blank
 func (r *FakeRepository) NewRecord(value interface{}) bool {
+	r.record("NewRecord", value)
 	return false
 }
 
@@ -396,90 +472,150 @@ func (r *FakeRepository) RecordNotFound() bool {
 
 // CreateTable create table for models
 func (r *FakeRepository) CreateTable(models ...interface{}) Repository {
-	return r
+	return r.record("CreateTable", models)
 }
 
 // DropTable drop table for models
 func (r *FakeRepository) DropTable(values ...interface{}) Repository {
-	return r
+	return r.record("DropTable", values)
 }
 
 // DropTableIfExists drop table if it is exist
 func (r *FakeRepository) DropTableIfExists(values ...interface{}) Repository {
-	return r
+	return r.record("DropTableIfExists", values)
+}
+
+// DryRun enable dry run mode, FakeRepository doesn't build statements anyway
+func (r *FakeRepository) DryRun(enable bool) Repository {
+	clone := r.record("DryRun", enable).Clone()
+	if enable {
+		clone.Values()["gorm:dry_run"] = true
+	} else {
+		delete(clone.Values(), "gorm:dry_run")
+	}
+	return clone
+}
+
+// DryRunStatements return nil as FakeRepository doesn't build statements
+func (r *FakeRepository) DryRunStatements() []Statement {
+	return nil
+}
+
+// MigrationPlan return nil as FakeRepository doesn't build statements
+func (r *FakeRepository) MigrationPlan(values ...interface{}) ([]string, error) {
+	r.record("MigrationPlan", values)
+	return nil, nil
 }
 
 // HasTable check has table or not
 func (r *FakeRepository) HasTable(value interface{}) bool {
+	r.record("HasTable", value)
 	return false
 }
 
 // AutoMigrate run auto migration for given models, will only add missing fields, won't delete/change current data
 func (r *FakeRepository) AutoMigrate(values ...interface{}) Repository {
-	return r
+	return r.record("AutoMigrate", values)
+}
+
+// WithAutoMigrateOptions set options of `AutoMigrate`
+func (r *FakeRepository) WithAutoMigrateOptions(options AutoMigrateOptions) Repository {
+	clone := r.record("WithAutoMigrateOptions", options).Clone()
+	clone.Values()["gorm:auto_migrate_modify_columns"] = options.ModifyColumns
+	clone.Values()["gorm:auto_migrate_destructive"] = options.Destructive
+	return clone
 }
 
 // ModifyColumn modify column to type
 func (r *FakeRepository) ModifyColumn(column string, typ string) Repository {
-	return r
+	return r.record("ModifyColumn", column, typ)
+}
+
+// RenameColumn rename a column
+func (r *FakeRepository) RenameColumn(oldName string, newName string) Repository {
+	return r.record("RenameColumn", oldName, newName)
+}
+
+// RenameTable rename a table
+func (r *FakeRepository) RenameTable(oldName string, newName string) Repository {
+	return r.record("RenameTable", oldName, newName)
+}
+
+// AlterColumnNullability make a column nullable or not
+func (r *FakeRepository) AlterColumnNullability(column string, nullable bool) Repository {
+	return r.record("AlterColumnNullability", column, nullable)
 }
 
 // DropColumn drop a column
 func (r *FakeRepository) DropColumn(column string) Repository {
-	return r
+	return r.record("DropColumn", column)
 }
 
 // AddIndex add index for columns with given name
 func (r *FakeRepository) AddIndex(indexName string, columns ...string) Repository {
-	return r
+	return r.record("AddIndex", indexName, columns)
 }
 
 // AddUniqueIndex add unique index for columns with given name
 func (r *FakeRepository) AddUniqueIndex(indexName string, columns ...string) Repository {
-	return r
+	return r.record("AddUniqueIndex", indexName, columns)
+}
+
+// CreateIndex add index with options
+func (r *FakeRepository) CreateIndex(index Index) Repository {
+	return r.record("CreateIndex", index)
 }
 
 // RemoveIndex remove index with name
 func (r *FakeRepository) RemoveIndex(indexName string) Repository {
-	return r
+	return r.record("RemoveIndex", indexName)
 }
 
 // AddForeignKey Add foreign key to the given scope, e.g:
 //     db.Model(&User{}).AddForeignKey("city_id", "cities(id)", "RESTRICT", "RESTRICT")
 func (r *FakeRepository) AddForeignKey(field string, dest string, onDelete string, onUpdate string) Repository {
-	return r
+	return r.record("AddForeignKey", field, dest, onDelete, onUpdate)
 }
 
 // RemoveForeignKey Remove foreign key from the given scope, e.g:
 //     db.Model(&User{}).RemoveForeignKey("city_id", "cities(id)")
 func (r *FakeRepository) RemoveForeignKey(field string, dest string) Repository {
-	return r
+	return r.record("RemoveForeignKey", field, dest)
 }
 
-// Association start `Association Mode` to handler relations things easir in that mode, refer: https://jinzhu.github.io/gorm/associations.html#association-mode
+// Association record the call, the returned association has an error as FakeRepository doesn't handle relationships
 func (r *FakeRepository) Association(column string) *Association {
-	return nil
+	r.record("Association", column)
+	return &Association{column: column, err: errors.New("gorm: associations are not supported by FakeRepository")}
 }
 
-// Preload preload associations with given conditions
+// Preload preload associations with given conditions, and `PreloadOptions` to order, limit or select preloaded records
 //    db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
+//    db.Preload("Orders", gorm.PreloadOptions{Order: "created_at DESC", Limit: 5}).Find(&users)
 func (r *FakeRepository) Preload(column string, conditions ...interface{}) Repository {
-	return r
+	return r.record("Preload", column, conditions).Clone().Search().Preload(column, conditions...).db
 }
 
 // Set set setting by name, which could be used in callbacks, will clone a new db, and update its setting
 func (r *FakeRepository) Set(name string, value interface{}) Repository {
-	return r
+	clone := r.record("Set", name, value).Clone()
+	clone.Values()[name] = value
+	return clone
 }
 
 // InstantSet instant set setting, will affect current db
 func (r *FakeRepository) InstantSet(name string, value interface{}) Repository {
+	r.record("InstantSet", name, value)
+	if r.values == nil {
+		r.values = map[string]interface{}{}
+	}
+	r.values[name] = value
 	return r
 }
 
 // Get get setting by name
 func (r *FakeRepository) Get(name string) (value interface{}, ok bool) {
-	value, ok = nil, false
+	value, ok = r.values[name]
 	return
 }
 
@@ -491,20 +627,11 @@ func (r *FakeRepository) SetJoinTableHandler(source interface{}, column string,
 func (r *FakeRepository) AddError(err error) error {
 	if err != nil {
 		if err != ErrRecordNotFound {
-			if r.logMode == 0 {
-				go r.Print(fileWithLineNum(), err)
-			} else {
-				r.Log(err)
-			}
-
-			errs := Errors(r.GetErrors())
-			errs = errs.Add(err)
-			if len(errs) > 1 {
-				err = errs
+			if r.logger != nil {
+				r.logger.Error(r.Context(), "%v", err)
 			}
 		}
-
-		r.SetError(err)
+		return addError(r, err)
 	}
 	return err
 }
@@ -604,6 +731,13 @@ func (r *FakeRepository) SetDialect(d Dialect) Repository {
 	return r
 }
 
+func (r *FakeRepository) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Private Methods For DB
 ////////////////////////////////////////////////////////////////////////////////
@@ -613,12 +747,18 @@ func (r *FakeRepository) Clone() Repository {
 		db:                r.db,
 		parent:            r.parent,
 		logger:            r.logger,
-		logMode:           r.logMode,
+		logLevel:          r.logLevel,
 		values:            map[string]interface{}{},
 		value:             r.value,
 		err:               r.Error(),
 		blockGlobalUpdate: r.blockGlobalUpdate,
-		dialect:           newDialect(r.dialect.GetName(), r.db),
+		ctx:               r.ctx,
+		state:             r.fakeState(),
+		chain:             r.chain,
+	}
+
+	if r.dialect != nil {
+		db.dialect = newDialect(r.dialect.GetName(), r.db)
 	}
 
 	for key, value := range r.values {
@@ -635,38 +775,39 @@ func (r *FakeRepository) Clone() Repository {
 	return db
 }
 
+// Print log v at info level
 func (r *FakeRepository) Print(v ...interface{}) {
-	r.logger.Print(v...)
+	if r.logger != nil {
+		r.logger.Info(r.Context(), "%v", fmt.Sprint(v...))
+	}
 }
 
+// Log log v at info level, same as `Print`
 func (r *FakeRepository) Log(v ...interface{}) {
-	if r != nil && r.logMode == 2 {
-		r.Print(append([]interface{}{"log", fileWithLineNum()}, v...)...)
+	if r != nil {
+		r.Print(v...)
 	}
 }
 
+// Slog log executed sql, which was started at t
 func (r *FakeRepository) Slog(sql string, t time.Time, vars ...interface{}) {
-	if r.logMode == 2 {
-		r.Print("sql", fileWithLineNum(), NowFunc().Sub(t), sql, vars, r.RowsAffected())
+	if r.logger != nil {
+		r.logger.Trace(r.Context(), t, func() (string, int64) {
+			return explainSQL(r.Dialect(), sql, vars...), r.RowsAffected()
+		}, r.Error())
 	}
 }
 
+// Mock set data that will be copied to the result of method, whatever conditions are used
 func (r *FakeRepository) Mock(method string, data interface{}) *FakeRepository {
-	if r.mockData == nil {
-		r.mockData = make(map[string]interface{})
-	}
-	r.mockData[method] = data
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
 
+	state.mockData[method] = data
 	return r
 }
 
 func (r *FakeRepository) Expect(err error) {
 	r.SetError(err)
 }
-
-func (r *FakeRepository) copyData(name string, out interface{})  {
-	md := r.mockData[name]
-	if md != nil {
-		copier.Copy(out, md)
-	}
-}
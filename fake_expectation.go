@@ -0,0 +1,428 @@
+package gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jinzhu/copier"
+)
+
+const (
+	fakeQuery  = "Query"
+	fakeCreate = "Create"
+	fakeSave   = "Save"
+	fakeUpdate = "Update"
+	fakeDelete = "Delete"
+)
+
+// TestingT is the subset of `*testing.T` used to report expectation failures
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// fakeState is shared by a FakeRepository and all relations created from it
+type fakeState struct {
+	mu           sync.Mutex
+	mockData     map[string]interface{}
+	expectations []*Expectation
+	unexpected   []string
+	calls        []FakeCall
+	chains       int
+}
+
+// Expectation describes a call expected by a FakeRepository, and the result it returns
+//     fake.ExpectQuery("First").WithConditions("name = ?", "jinzhu").Returns(&User{Name: "jinzhu"})
+//     fake.ExpectCreate(&User{}).ReturnsError(errors.New("duplicated"))
+type Expectation struct {
+	kind   string
+	method string
+	value  interface{}
+
+	whereConditions []map[string]interface{}
+	orConditions    []map[string]interface{}
+	notConditions   []map[string]interface{}
+	orders          []interface{}
+	limit           interface{}
+	offset          interface{}
+	attrs           map[string]interface{}
+	matchWhere      bool
+	matchOr         bool
+	matchNot        bool
+	matchOrder      bool
+	matchLimit      bool
+	matchOffset     bool
+	matchAttrs      bool
+
+	data interface{}
+	err  error
+	met  bool
+}
+
+// ExpectQuery expect a query method, e.g. `First`, `Find`, `Count`, `Pluck`
+func (r *FakeRepository) ExpectQuery(method string) *Expectation {
+	return r.expect(&Expectation{kind: fakeQuery, method: method})
+}
+
+// ExpectCreate expect `Create` with a value of the same type, a non-blank value must be deeply equal
+func (r *FakeRepository) ExpectCreate(value interface{}) *Expectation {
+	return r.expect(&Expectation{kind: fakeCreate, value: value})
+}
+
+// ExpectSave expect `Save` with a value of the same type, a non-blank value must be deeply equal
+func (r *FakeRepository) ExpectSave(value interface{}) *Expectation {
+	return r.expect(&Expectation{kind: fakeSave, value: value})
+}
+
+// ExpectUpdate expect one of `Update`, `Updates`, `UpdateColumn`, `UpdateColumns` on a model of the same type, a non-blank model must be deeply equal
+func (r *FakeRepository) ExpectUpdate(value interface{}) *Expectation {
+	return r.expect(&Expectation{kind: fakeUpdate, value: value})
+}
+
+// ExpectDelete expect `Delete` with a value of the same type, a non-blank value must be deeply equal
+func (r *FakeRepository) ExpectDelete(value interface{}) *Expectation {
+	return r.expect(&Expectation{kind: fakeDelete, value: value})
+}
+
+// AssertExpectationsMet report expectations that were not met and calls that were not expected, returns true if there is none
+func (r *FakeRepository) AssertExpectationsMet(t TestingT) bool {
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	ok := true
+	for _, expectation := range state.expectations {
+		if !expectation.met {
+			t.Errorf("gorm: expected %v, but it was not called", expectation)
+			ok = false
+		}
+	}
+
+	for _, call := range state.unexpected {
+		t.Errorf("gorm: unexpected call %v", call)
+		ok = false
+	}
+	return ok
+}
+
+// WithConditions expect a `Where` condition, call it multiple times to expect multiple conditions in order
+func (e *Expectation) WithConditions(query interface{}, args ...interface{}) *Expectation {
+	e.whereConditions = append(e.whereConditions, map[string]interface{}{"query": query, "args": args})
+	e.matchWhere = true
+	return e
+}
+
+// WithOr expect an `Or` condition, call it multiple times to expect multiple conditions in order
+func (e *Expectation) WithOr(query interface{}, args ...interface{}) *Expectation {
+	e.orConditions = append(e.orConditions, map[string]interface{}{"query": query, "args": args})
+	e.matchOr = true
+	return e
+}
+
+// WithNot expect a `Not` condition, call it multiple times to expect multiple conditions in order
+func (e *Expectation) WithNot(query interface{}, args ...interface{}) *Expectation {
+	e.notConditions = append(e.notConditions, map[string]interface{}{"query": query, "args": args})
+	e.matchNot = true
+	return e
+}
+
+// WithOrder expect an `Order`, call it multiple times to expect multiple orders in order
+func (e *Expectation) WithOrder(value interface{}) *Expectation {
+	e.orders = append(e.orders, value)
+	e.matchOrder = true
+	return e
+}
+
+// WithLimit expect a `Limit`
+func (e *Expectation) WithLimit(limit interface{}) *Expectation {
+	e.limit = limit
+	e.matchLimit = true
+	return e
+}
+
+// WithOffset expect an `Offset`
+func (e *Expectation) WithOffset(offset interface{}) *Expectation {
+	e.offset = offset
+	e.matchOffset = true
+	return e
+}
+
+// WithAttrs expect updated attributes, given like arguments of `Update` or `Updates`, columns are compared by their db names,
+// values are compared as strings
+//     fake.ExpectUpdate(&User{}).WithAttrs("name", "hello")
+//     fake.ExpectUpdate(&User{}).WithAttrs(map[string]interface{}{"name": "hello", "age": 18})
+func (e *Expectation) WithAttrs(attrs ...interface{}) *Expectation {
+	e.attrs = fakeAttrs(toSearchableMap(attrs...))
+	e.matchAttrs = true
+	return e
+}
+
+// Returns set data that will be copied to the result when the expectation is met, the data is copied deeply for each call
+func (e *Expectation) Returns(data interface{}) *Expectation {
+	e.data = data
+	return e
+}
+
+// ReturnsError set error that will be added to the returned relation when the expectation is met
+func (e *Expectation) ReturnsError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+func (e *Expectation) String() string {
+	name := e.method
+	if name == "" {
+		name = e.kind
+	}
+	if e.value != nil {
+		name = fmt.Sprintf("%v(%T)", name, e.value)
+	}
+
+	var (
+		whereConditions, orConditions, notConditions []map[string]interface{}
+		orders                                       []interface{}
+		limit, offset                                interface{}
+	)
+	if e.matchWhere {
+		whereConditions = e.whereConditions
+	}
+	if e.matchOr {
+		orConditions = e.orConditions
+	}
+	if e.matchNot {
+		notConditions = e.notConditions
+	}
+	if e.matchOrder {
+		orders = e.orders
+	}
+	if e.matchLimit {
+		limit = e.limit
+	}
+	if e.matchOffset {
+		offset = e.offset
+	}
+	return describeFakeCall(name, whereConditions, orConditions, notConditions, orders, limit, offset, e.attrs)
+}
+
+func (e *Expectation) matches(kind, method string, value interface{}, search *Search, attrs map[string]interface{}) bool {
+	if e.met || e.kind != kind || (e.method != "" && e.method != method) {
+		return false
+	}
+
+	if e.value != nil && !fakeValueMatches(e.value, value) {
+		return false
+	}
+
+	return (!e.matchWhere || fakeConditionsEqual(e.whereConditions, search.whereConditions)) &&
+		(!e.matchOr || fakeConditionsEqual(e.orConditions, search.orConditions)) &&
+		(!e.matchNot || fakeConditionsEqual(e.notConditions, search.notConditions)) &&
+		(!e.matchOrder || reflect.DeepEqual(e.orders, search.orders)) &&
+		(!e.matchLimit || reflect.DeepEqual(e.limit, search.limit)) &&
+		(!e.matchOffset || reflect.DeepEqual(e.offset, search.offset)) &&
+		(!e.matchAttrs || fakeAttrsEqual(e.attrs, attrs))
+}
+
+func (r *FakeRepository) fakeState() *fakeState {
+	if r.state == nil {
+		r.state = &fakeState{mockData: map[string]interface{}{}}
+	}
+	return r.state
+}
+
+func (r *FakeRepository) expect(expectation *Expectation) *Expectation {
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.expectations = append(state.expectations, expectation)
+	return expectation
+}
+
+// call resolve a finisher method with the first matched expectation, or the mocked data of the method
+func (r *FakeRepository) call(kind, method string, value interface{}, where ...interface{}) Repository {
+	return r.resolve(kind, method, value, nil, where)
+}
+
+// callUpdate resolve an update method, values are given like arguments of `Updates`
+func (r *FakeRepository) callUpdate(method string, values interface{}) Repository {
+	return r.resolve(fakeUpdate, method, r.value, fakeAttrs(values), nil)
+}
+
+func (r *FakeRepository) resolve(kind, method string, value interface{}, attrs map[string]interface{}, where []interface{}) Repository {
+	clone := r.Clone().(*FakeRepository)
+	if len(where) > 0 {
+		clone.search.Where(where[0], where[1:]...)
+	}
+
+	state := clone.fakeState()
+	state.mu.Lock()
+	var matched *Expectation
+	for _, expectation := range state.expectations {
+		if expectation.matches(kind, method, value, clone.search, attrs) {
+			expectation.met = true
+			matched = expectation
+			break
+		}
+	}
+
+	data, mocked := state.mockData[method]
+	if matched == nil && !mocked {
+		state.unexpected = append(state.unexpected, describeFakeCall(fmt.Sprintf("%v(%T)", method, value),
+			clone.search.whereConditions, clone.search.orConditions, clone.search.notConditions,
+			clone.search.orders, clone.search.limit, clone.search.offset, attrs))
+	}
+	state.mu.Unlock()
+
+	if matched != nil {
+		fakeCopy(value, matched.data)
+		clone.AddError(matched.err)
+	} else if mocked {
+		fakeCopy(value, data)
+	}
+	return clone
+}
+
+func describeFakeCall(name string, whereConditions, orConditions, notConditions []map[string]interface{}, orders []interface{}, limit, offset interface{}, attrs map[string]interface{}) string {
+	var parts = []string{name}
+	describeConditions := func(prefix string, conditions []map[string]interface{}) {
+		for _, condition := range conditions {
+			parts = append(parts, fmt.Sprintf("%v %v %v", prefix, condition["query"], condition["args"]))
+		}
+	}
+
+	describeConditions("WHERE", whereConditions)
+	describeConditions("OR", orConditions)
+	describeConditions("NOT", notConditions)
+	for _, order := range orders {
+		parts = append(parts, fmt.Sprintf("ORDER %v", order))
+	}
+	if limit != nil && fmt.Sprint(limit) != "-1" {
+		parts = append(parts, fmt.Sprintf("LIMIT %v", limit))
+	}
+	if offset != nil && fmt.Sprint(offset) != "-1" {
+		parts = append(parts, fmt.Sprintf("OFFSET %v", offset))
+	}
+	if attrs != nil {
+		parts = append(parts, fmt.Sprintf("SET %v", attrs))
+	}
+	return strings.Join(parts, " ")
+}
+
+func fakeConditionsEqual(expected, actual []map[string]interface{}) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for idx, condition := range expected {
+		if !reflect.DeepEqual(condition["query"], actual[idx]["query"]) {
+			return false
+		}
+
+		expectedArgs, _ := condition["args"].([]interface{})
+		actualArgs, _ := actual[idx]["args"].([]interface{})
+		if len(expectedArgs) != len(actualArgs) || (len(expectedArgs) > 0 && !reflect.DeepEqual(expectedArgs, actualArgs)) {
+			return false
+		}
+	}
+	return true
+}
+
+func fakeValueMatches(expected, actual interface{}) bool {
+	expectedValue := indirect(reflect.ValueOf(expected))
+	actualValue := indirect(reflect.ValueOf(actual))
+	if !actualValue.IsValid() || expectedValue.Type() != actualValue.Type() {
+		return false
+	}
+
+	if isBlank(expectedValue) || (expectedValue.Kind() == reflect.Slice && expectedValue.Len() == 0) {
+		return true
+	}
+	return reflect.DeepEqual(expectedValue.Interface(), actualValue.Interface())
+}
+
+// fakeAttrs convert updated values to a map of db names, nil values are converted to an empty map
+func fakeAttrs(values interface{}) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	if values != nil {
+		for key, value := range convertInterfaceToMap(values, false) {
+			attrs[ToDBName(key)] = value
+		}
+	}
+	return attrs
+}
+
+// fakeAttrsEqual compare values as strings, so `int` attrs match `int64` fields
+func fakeAttrsEqual(expected, actual map[string]interface{}) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for column, value := range expected {
+		if actualValue, ok := actual[column]; !ok || !equalAsString(value, actualValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeCopy copy data to out deeply, so results don't share slices, maps and pointers with mocked data
+func fakeCopy(out interface{}, data interface{}) {
+	if out == nil || data == nil {
+		return
+	}
+
+	outValue := indirect(reflect.ValueOf(out))
+	dataValue := indirect(reflect.ValueOf(data))
+	if outValue.CanSet() && dataValue.IsValid() && dataValue.Type().AssignableTo(outValue.Type()) {
+		outValue.Set(fakeDeepCopy(dataValue))
+		return
+	}
+	copier.CopyWithOption(out, data, copier.Option{DeepCopy: true})
+}
+
+// fakeDeepCopy copy slices, maps, pointers and exported fields of value recursively, values with cyclic pointers are not supported
+func fakeDeepCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.New(value.Type()).Elem()
+		if value.Kind() == reflect.Ptr {
+			result.Set(reflect.New(value.Elem().Type()))
+			result.Elem().Set(fakeDeepCopy(value.Elem()))
+		} else {
+			result.Set(fakeDeepCopy(value.Elem()))
+		}
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for idx := 0; idx < value.Len(); idx++ {
+			result.Index(idx).Set(fakeDeepCopy(value.Index(idx)))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			result.SetMapIndex(key, fakeDeepCopy(value.MapIndex(key)))
+		}
+		return result
+	case reflect.Struct:
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+		for idx := 0; idx < value.NumField(); idx++ {
+			if field := result.Field(idx); field.CanSet() {
+				field.Set(fakeDeepCopy(value.Field(idx)))
+			}
+		}
+		return result
+	}
+	return value
+}
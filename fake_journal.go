@@ -0,0 +1,93 @@
+package gorm
+
+// FakeCall is a method call recorded by a FakeRepository
+type FakeCall struct {
+	// Method is the name of the called method, e.g. `Where`, `Create`
+	Method string
+	// Args are the arguments of the call in order, variadic arguments are kept as one slice
+	Args []interface{}
+	// Chain identifies calls made on the same relation, every call on the root FakeRepository starts a new chain
+	Chain int
+}
+
+// Calls return all recorded calls in order
+func (r *FakeRepository) Calls() []FakeCall {
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return append([]FakeCall{}, state.calls...)
+}
+
+// CallsTo return recorded calls to any of methods
+//     fake.CallsTo("Create", "Save")
+func (r *FakeRepository) CallsTo(methods ...string) []FakeCall {
+	return r.filterCalls(func(call FakeCall) bool {
+		for _, method := range methods {
+			if call.Method == method {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// CallsInChain return recorded calls belonging to chain
+func (r *FakeRepository) CallsInChain(chain int) []FakeCall {
+	return r.filterCalls(func(call FakeCall) bool {
+		return call.Chain == chain
+	})
+}
+
+// Writes return recorded calls that would change data, e.g. `Create`, `Save`, `Update`, `Delete`, `Exec`
+func (r *FakeRepository) Writes() []FakeCall {
+	return r.CallsTo("Create", "CreateInBatches", "Save", "Update", "Updates", "UpdateColumn", "UpdateColumns", "Delete", "Exec")
+}
+
+// ResetCalls clear recorded calls
+func (r *FakeRepository) ResetCalls() {
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.calls = nil
+}
+
+func (r *FakeRepository) filterCalls(fc func(FakeCall) bool) (calls []FakeCall) {
+	for _, call := range r.Calls() {
+		if fc(call) {
+			calls = append(calls, call)
+		}
+	}
+	return
+}
+
+// record append a call to the journal, returns the relation the call belongs to
+func (r *FakeRepository) record(method string, args ...interface{}) *FakeRepository {
+	state := r.fakeState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	chain := r.chain
+	if chain == 0 {
+		state.chains++
+		chain = state.chains
+	}
+	state.calls = append(state.calls, FakeCall{Method: method, Args: args, Chain: chain})
+
+	if chain == r.chain {
+		return r
+	}
+	// values and search are copied, so that the relation won't change the original one
+	chained := *r
+	chained.chain = chain
+	chained.values = map[string]interface{}{}
+	for key, value := range r.values {
+		chained.values[key] = value
+	}
+	if r.search != nil {
+		chained.search = r.search.clone()
+		chained.search.db = &chained
+	}
+	return &chained
+}
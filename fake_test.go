@@ -0,0 +1,219 @@
+package gorm_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFakeExpectQueryMatchesConditions(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	fake.ExpectQuery("First").WithConditions("id = ?", 2).Returns(&User{Id: 2, Name: "second"})
+	fake.ExpectQuery("First").WithConditions("id = ?", 1).Returns(&User{Id: 1, Name: "first"})
+
+	var user1, user2 User
+	if err := fake.Where("id = ?", 1).First(&user1).Error(); err != nil {
+		t.Errorf("No error should happen, but got %v", err)
+	}
+	fake.First(&user2, "id = ?", 2)
+
+	if user1.Name != "first" || user2.Name != "second" {
+		t.Errorf("Should return data of matched expectations, but got %v, %v", user1.Name, user2.Name)
+	}
+
+	if !fake.AssertExpectationsMet(t) {
+		t.Errorf("All expectations should be met")
+	}
+}
+
+func TestFakeExpectQueryMatchesSearch(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	fake.ExpectQuery("Find").
+		WithConditions("age > ?", 10).
+		WithOr("name = ?", "jinzhu").
+		WithNot("role", "admin").
+		WithOrder("age desc").
+		WithLimit(5).
+		WithOffset(10).
+		Returns([]User{{Name: "found"}})
+
+	var users []User
+	fake.Where("age > ?", 11).Or("name = ?", "jinzhu").Not("role", "admin").Order("age desc").Limit(5).Offset(10).Find(&users)
+	if len(users) != 0 {
+		t.Errorf("Should not match expectation with different arguments")
+	}
+
+	fake.Where("age > ?", 10).Or("name = ?", "jinzhu").Not("role", "admin").Order("age desc").Limit(5).Offset(10).Find(&users)
+	if len(users) != 1 || users[0].Name != "found" {
+		t.Errorf("Should return data of matched expectation, but got %v", users)
+	}
+
+	recorder := &recordingT{}
+	if fake.AssertExpectationsMet(recorder) || len(recorder.errors) != 1 {
+		t.Errorf("Should report the unexpected call, but got %v", recorder.errors)
+	}
+}
+
+func TestFakeExpectWrites(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	duplicated := errors.New("duplicated")
+	fake.ExpectCreate(&User{}).Returns(&User{Id: 10, Name: "created"})
+	fake.ExpectCreate(&User{}).ReturnsError(duplicated)
+	fake.ExpectUpdate(&User{}).WithConditions("name = ?", "jinzhu")
+	fake.ExpectDelete(&User{Name: "deleted"})
+
+	user := User{Name: "created"}
+	if err := fake.Create(&user).Error(); err != nil || user.Id != 10 {
+		t.Errorf("Should create with returned data, but got %v, %v", user.Id, err)
+	}
+
+	if err := fake.Create(&User{Name: "duplicated"}).Error(); err != duplicated {
+		t.Errorf("Should return expected error, but got %v", err)
+	}
+
+	if err := fake.Model(&User{}).Where("name = ?", "jinzhu").Update("age", 20).Error(); err != nil {
+		t.Errorf("No error should happen, but got %v", err)
+	}
+
+	fake.Delete(&User{Name: "other"})
+
+	recorder := &recordingT{}
+	if fake.AssertExpectationsMet(recorder) {
+		t.Errorf("Should fail when expectations are not met")
+	}
+
+	if len(recorder.errors) != 2 {
+		t.Errorf("Should report the unmet delete and the unexpected delete, but got %v", recorder.errors)
+	}
+}
+
+func TestFakeExpectUpdateWithAttrs(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	fake.ExpectUpdate(&User{}).WithAttrs("age", 20)
+	fake.ExpectUpdate(&User{}).WithAttrs(map[string]interface{}{"name": "jinzhu", "age": 18})
+
+	fake.Model(&User{}).Update("age", 21)
+	fake.Model(&User{}).Update("age", 20)
+	fake.Model(&User{}).Updates(User{Name: "jinzhu", Age: 18})
+
+	recorder := &recordingT{}
+	fake.AssertExpectationsMet(recorder)
+	if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], "SET map[age:21]") {
+		t.Errorf("Should only report the update with unexpected attrs, but got %v", recorder.errors)
+	}
+}
+
+func TestFakeReturnsCopiedData(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	users := []User{{Name: "fixture", Emails: []Email{{Email: "fixture@example.org"}}}}
+	fake.ExpectQuery("Find").Returns(users)
+
+	var results []User
+	fake.Find(&results)
+	results[0].Name = "changed"
+	results[0].Emails[0].Email = "changed@example.org"
+	if users[0].Name != "fixture" || users[0].Emails[0].Email != "fixture@example.org" {
+		t.Errorf("Results should not share data with expectations, but got %v", users[0])
+	}
+}
+
+func TestFakeMockStillWorks(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	fake.Mock("Find", []User{{Name: "mocked"}})
+
+	var users []User
+	fake.Where("name = ?", "anything").Find(&users)
+	if len(users) != 1 || users[0].Name != "mocked" {
+		t.Errorf("Should return mocked data, but got %v", users)
+	}
+
+	if !fake.AssertExpectationsMet(t) {
+		t.Errorf("Mocked calls should not be reported as unexpected")
+	}
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	user := User{Name: "journal"}
+
+	fake.Create(&user)
+	fake.Model(&user).Where("name = ?", "journal").Updates(map[string]interface{}{"age": 18})
+	fake.Where("name = ?", "journal").Delete(&User{})
+	fake.Where("age > ?", 10).Find(&[]User{})
+
+	if calls := fake.Calls(); len(calls) != 8 {
+		t.Fatalf("Should record all calls, but got %v", calls)
+	}
+
+	writes := fake.Writes()
+	if len(writes) != 3 || writes[0].Method != "Create" || writes[1].Method != "Updates" || writes[2].Method != "Delete" {
+		t.Errorf("Should return writes in order, but got %v", writes)
+	}
+
+	if writes[0].Args[0] != &user {
+		t.Errorf("Should record arguments of the call")
+	}
+
+	if updates, ok := writes[1].Args[0].(map[string]interface{}); !ok || updates["age"] != 18 {
+		t.Errorf("Should record updated attributes, but got %v", writes[1].Args)
+	}
+
+	chain := fake.CallsInChain(writes[1].Chain)
+	if len(chain) != 3 || chain[0].Method != "Model" || chain[1].Method != "Where" || chain[2].Method != "Updates" {
+		t.Errorf("Should group calls of the same chain, but got %v", chain)
+	}
+
+	if where := fake.CallsTo("Where"); len(where) != 3 || where[0].Chain == where[1].Chain {
+		t.Errorf("Calls on the root repository should start new chains, but got %v", where)
+	}
+
+	fake.Transaction(func(tx gorm.Repository) error {
+		tx.Create(&User{Name: "in transaction"})
+		return tx.Save(&User{Name: "in transaction"}).Error()
+	})
+
+	transaction := fake.CallsTo("Transaction")
+	if len(transaction) != 1 || len(fake.CallsInChain(transaction[0].Chain)) != 3 {
+		t.Errorf("Calls inside a transaction should belong to its chain")
+	}
+
+	fake.ResetCalls()
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Should clear recorded calls")
+	}
+}
+
+func TestFakeRecordsAssociationCalls(t *testing.T) {
+	fake := &gorm.FakeRepository{}
+	user := User{Id: 1, Name: "association"}
+
+	association := fake.Model(&user).Association("Languages")
+	association.Append(&Language{Name: "en"})
+	if count := association.Find(&[]Language{}).Count(); count != 0 || association.Error() == nil {
+		t.Errorf("Fake association should count nothing with an error, but got %v, %v", count, association.Error())
+	}
+
+	if writes := fake.Writes(); len(writes) != 0 {
+		t.Errorf("Should record no writes for associations, but got %v", writes)
+	}
+
+	calls := fake.CallsTo("Association")
+	if len(calls) != 1 || calls[0].Args[0] != "Languages" {
+		t.Fatalf("Should record the association call, but got %v", calls)
+	}
+
+	if chain := fake.CallsInChain(calls[0].Chain); len(chain) != 2 || chain[0].Method != "Model" {
+		t.Errorf("Association call should belong to the chain of its model, but got %v", chain)
+	}
+}
@@ -0,0 +1,496 @@
+package gorm
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/inflection"
+)
+
+// GenerateOptions options of `GenerateModels`
+type GenerateOptions struct {
+	// Package package name of the generated source, `models` by default
+	Package string
+	// Tables tables to generate models for, all tables of the current database by default
+	Tables []string
+}
+
+// GenerateModels generate Go source of models with `gorm` tags from tables of the current database, which covers column names, types,
+// sizes, primary keys, `NOT NULL`, defaults and indexes; tables only joining two other tables become many2many fields of their models
+//     source, err := gorm.GenerateModels(db, gorm.GenerateOptions{Package: "models"})
+func GenerateModels(db Repository, options GenerateOptions) ([]byte, error) {
+	if options.Package == "" {
+		options.Package = "models"
+	}
+
+	inspector := NewInspector(db)
+	tableNames := options.Tables
+	if len(tableNames) == 0 {
+		tables, err := inspector.Tables()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, table := range tables {
+			tableNames = append(tableNames, table.Name)
+		}
+	}
+
+	var (
+		schemas    []*generatorTable
+		tableIndex = map[string]*generatorTable{}
+	)
+	for _, tableName := range tableNames {
+		schema := &generatorTable{name: tableName}
+		var err error
+		if schema.columns, err = inspector.ColumnTypes(tableName); err != nil {
+			return nil, err
+		}
+		if schema.indexes, err = inspector.Indexes(tableName); err != nil {
+			return nil, err
+		}
+		if schema.foreignKeys, err = inspector.ForeignKeys(tableName); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+		tableIndex[tableName] = schema
+	}
+
+	var (
+		generator = &modelGenerator{db: db, models: map[string]*generatedModel{}}
+		joins     [][2]joinTableReference
+		joinNames []string
+	)
+	for _, schema := range schemas {
+		if references, ok := schema.joinTableReferences(tableIndex); ok {
+			joins = append(joins, references)
+			joinNames = append(joinNames, schema.name)
+			continue
+		}
+
+		model, err := generator.generateModel(schema)
+		if err != nil {
+			return nil, err
+		}
+		generator.order = append(generator.order, schema.name)
+		generator.models[schema.name] = model
+	}
+
+	for idx, references := range joins {
+		generator.addMany2Many(joinNames[idx], references[0], references[1])
+		if references[0].table != references[1].table {
+			generator.addMany2Many(joinNames[idx], references[1], references[0])
+		}
+	}
+	return generator.render(options.Package)
+}
+
+// generatorTable schema of a table inspected for generating its model
+type generatorTable struct {
+	name        string
+	columns     []ColumnType
+	indexes     []Index
+	foreignKeys []ForeignKey
+}
+
+// primaryKeys return names of primary key columns
+func (table *generatorTable) primaryKeys() (names []string) {
+	for _, column := range table.columns {
+		if column.PrimaryKey {
+			names = append(names, column.Name)
+		}
+	}
+	return
+}
+
+// joinTableReference a column of a join table referencing the primary key of another table
+type joinTableReference struct {
+	column           string
+	table            string
+	referencedColumn string
+}
+
+// joinTableReferences return references of the table if it only has two columns referencing primary keys of two generated tables,
+// references are found from foreign keys, or column names like `user_id` referencing column `id` of table `users`
+func (table *generatorTable) joinTableReferences(tables map[string]*generatorTable) (references [2]joinTableReference, ok bool) {
+	if len(table.columns) != 2 {
+		return references, false
+	}
+
+	for idx, column := range table.columns {
+		var found bool
+		for _, foreignKey := range table.foreignKeys {
+			if len(foreignKey.Columns) == 1 && foreignKey.Columns[0] == column.Name {
+				if referenced, ok := tables[foreignKey.ReferencedTable]; ok {
+					primaryKeys := referenced.primaryKeys()
+					if len(primaryKeys) == 1 && primaryKeys[0] == foreignKey.ReferencedColumns[0] {
+						references[idx], found = joinTableReference{column.Name, referenced.name, primaryKeys[0]}, true
+					}
+				}
+			}
+		}
+
+		for _, referenced := range tables {
+			if primaryKeys := referenced.primaryKeys(); !found && referenced != table && len(primaryKeys) == 1 {
+				if column.Name == ToDBName(inflection.Singular(referenced.name))+"_"+primaryKeys[0] {
+					references[idx], found = joinTableReference{column.Name, referenced.name, primaryKeys[0]}, true
+				}
+			}
+		}
+
+		if !found {
+			return references, false
+		}
+	}
+	return references, true
+}
+
+type modelGenerator struct {
+	db     Repository
+	order  []string
+	models map[string]*generatedModel
+}
+
+type generatedModel struct {
+	name      string
+	tableName string
+	fields    []*generatedField
+}
+
+type generatedField struct {
+	name     string
+	typ      string
+	tags     []string
+	column   string
+	comments []string
+}
+
+func (generator *modelGenerator) generateModel(table *generatorTable) (*generatedModel, error) {
+	model := &generatedModel{name: toGoName(inflection.Singular(table.name)), tableName: table.name}
+	primaryKeys := table.primaryKeys()
+
+	for _, column := range table.columns {
+		field := &generatedField{name: toGoName(column.Name), column: column.Name}
+		goType := goTypeOfColumn(column)
+
+		if ToDBName(field.name) != column.Name {
+			field.addTag("column:"+column.Name)
+		}
+
+		if column.PrimaryKey {
+			if field.name != "ID" || len(primaryKeys) > 1 {
+				field.tags = append(field.tags, "primary_key")
+			}
+		} else {
+			if column.Nullable && goType.Kind() != reflect.Slice {
+				goType = reflect.PtrTo(goType)
+			}
+
+			if !column.Nullable {
+				field.tags = append(field.tags, "not null")
+			}
+		}
+
+		var sizeTag string
+		if column.Size > 0 && sizedColumnTypes[normalizeColumnType(column.DatabaseType)] {
+			sizeTag = fmt.Sprintf("size:%d", column.Size)
+		}
+
+		if value, ok := defaultValueOfColumn(generator.db.Dialect().GetName(), column, goType); ok {
+			field.addTag("default:"+value)
+		}
+
+		// compare type of the generated field with the column, specify the type if they differ
+		field.typ = goTypeName(goType)
+		dataType, err := generator.dataTypeOf(field.name, goType, append(append([]string{}, field.tags...), sizeTag))
+		if err != nil {
+			return nil, err
+		}
+
+		if typeName, _ := splitColumnType(dataType); normalizeColumnType(typeName) != normalizeColumnType(column.DatabaseType) {
+			typ := column.DatabaseType
+			if sizeTag != "" {
+				typ = fmt.Sprintf("%v(%d)", typ, column.Size)
+			}
+			field.addTag("type:"+typ)
+		} else if sizeTag != "" {
+			field.tags = append(field.tags, sizeTag)
+		}
+
+		model.fields = append(model.fields, field)
+	}
+
+	for _, index := range table.indexes {
+		generator.addIndexTags(model, index)
+	}
+	return model, nil
+}
+
+// dataTypeOf return the data type of a field built from a model of the field, refer `Dialect.DataTypeOf`
+func (generator *modelGenerator) dataTypeOf(name string, typ reflect.Type, tags []string) (dataType string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gorm: failed to generate field %v, got %v", name, r)
+		}
+	}()
+
+	modelType := reflect.StructOf([]reflect.StructField{{
+		Name: name,
+		Type: typ,
+		Tag:  reflect.StructTag(fmt.Sprintf(`gorm:"%v"`, tagValueEscaper.Replace(strings.Join(tags, ";")))),
+	}})
+	scope := generator.db.NewScope(reflect.New(modelType).Interface())
+	return scope.Dialect().DataTypeOf(scope.GetModelStruct().StructFields[0]), nil
+}
+
+// addIndexTags add tags of index to fields of its columns, indexes of expressions are skipped
+func (generator *modelGenerator) addIndexTags(model *generatedModel, index Index) {
+	for _, column := range index.Columns {
+		if column.Expression || model.fieldByColumn(column.Name) == nil {
+			return
+		}
+	}
+
+	// unique constraints of columns
+	if generator.db.Dialect().IsImplicitIndex(index) {
+		if len(index.Columns) == 1 {
+			field := model.fieldByColumn(index.Columns[0].Name)
+			field.tags = append(field.tags, "unique")
+		}
+		return
+	}
+
+	kind := "index"
+	if index.Unique {
+		kind = "unique_index"
+	}
+
+	// options are separated by commas, so the index is dropped if its name or condition contains one
+	if strings.ContainsAny(index.Name+index.Where, ",;`\n") {
+		field := model.fieldByColumn(index.Columns[0].Name)
+		field.comments = append(field.comments, fmt.Sprintf("%v %v is dropped, it can't be expressed in struct tags: %q", kind, index.Name, index.Where))
+		return
+	}
+
+	for idx, column := range index.Columns {
+		options := []string{index.Name}
+		if len(index.Columns) > 1 {
+			options = append(options, fmt.Sprintf("priority:%d", idx+1))
+		}
+		if column.Sort == "DESC" {
+			options = append(options, "sort:desc")
+		}
+		if idx == 0 {
+			if index.Type != "" && !defaultIndexTypes[strings.ToLower(index.Type)] {
+				options = append(options, "type:"+index.Type)
+			}
+			if index.Where != "" {
+				options = append(options, "where:"+index.Where)
+			}
+		}
+
+		field := model.fieldByColumn(column.Name)
+		field.addTagValue(kind, strings.Join(options, ","))
+	}
+}
+
+// addMany2Many add a many2many field referencing another model to the model of the reference's table
+func (generator *modelGenerator) addMany2Many(joinTable string, reference joinTableReference, association joinTableReference) {
+	model, associationModel := generator.models[reference.table], generator.models[association.table]
+
+	name := toGoName(strings.TrimSuffix(association.column, "_"+association.referencedColumn))
+	if name == "" || model.fieldByName(inflection.Plural(name)) != nil {
+		name = associationModel.name
+	}
+	name = inflection.Plural(name)
+	if model.fieldByName(name) != nil {
+		name += toGoName(joinTable)
+	}
+
+	model.fields = append(model.fields, &generatedField{
+		name: name,
+		typ:  "[]" + associationModel.name,
+		tags: []string{
+			"many2many:" + joinTable,
+			"jointable_foreignkey:" + reference.column,
+			"association_jointable_foreignkey:" + association.column,
+		},
+	})
+}
+
+func (generator *modelGenerator) render(packageName string) ([]byte, error) {
+	var (
+		body       bytes.Buffer
+		importTime bool
+	)
+
+	for _, tableName := range generator.order {
+		model := generator.models[tableName]
+		fmt.Fprintf(&body, "\n// %v model of table %v\ntype %v struct {\n", model.name, model.tableName, model.name)
+		for _, field := range model.fields {
+			importTime = importTime || strings.Contains(field.typ, "time.")
+			for _, comment := range field.comments {
+				fmt.Fprintf(&body, "\t// %v\n", comment)
+			}
+			if len(field.tags) > 0 {
+				fmt.Fprintf(&body, "\t%v %v `gorm:\"%v\"`\n", field.name, field.typ, tagValueEscaper.Replace(strings.Join(field.tags, ";")))
+			} else {
+				fmt.Fprintf(&body, "\t%v %v\n", field.name, field.typ)
+			}
+		}
+		body.WriteString("}\n")
+
+		if inflection.Plural(ToDBName(model.name)) != model.tableName {
+			fmt.Fprintf(&body, "\n// TableName return table name of %v\nfunc (%v) TableName() string {\n\treturn %q\n}\n", model.name, model.name, model.tableName)
+		}
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "package %v\n", packageName)
+	if importTime {
+		source.WriteString("\nimport \"time\"\n")
+	}
+	source.Write(body.Bytes())
+	return format.Source(source.Bytes())
+}
+
+func (model *generatedModel) fieldByColumn(column string) *generatedField {
+	for _, field := range model.fields {
+		if field.column != "" && field.column == column {
+			return field
+		}
+	}
+	return nil
+}
+
+func (model *generatedModel) fieldByName(name string) *generatedField {
+	for _, field := range model.fields {
+		if field.name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// addTag add tag setting to the field, settings containing `;` which separates settings, or a backtick which ends the struct tag,
+// can't be expressed in struct tags and are dropped with a comment
+func (field *generatedField) addTag(tag string) {
+	if strings.ContainsAny(tag, ";`\n") {
+		field.comments = append(field.comments, fmt.Sprintf("%q is dropped, it can't be expressed in struct tags", tag))
+		return
+	}
+	field.tags = append(field.tags, tag)
+}
+
+// addTagValue add value to tag setting like `index`, values of the same setting are joined with commas
+func (field *generatedField) addTagValue(key string, value string) {
+	for idx, tag := range field.tags {
+		if strings.HasPrefix(tag, key+":") {
+			field.tags[idx] = tag + "," + value
+			return
+		}
+	}
+	field.tags = append(field.tags, key+":"+value)
+}
+
+var (
+	// tagValueEscaper escape the value of struct tag `gorm`, which is unquoted by `reflect.StructTag.Get`
+	tagValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	// sizedColumnTypes column types of which the size is specified with tag `size`
+	sizedColumnTypes = map[string]bool{"varchar": true, "char": true, "nvarchar": true, "nchar": true, "varbinary": true, "binary": true}
+	// defaultIndexTypes types of indexes created by default
+	defaultIndexTypes = map[string]bool{"btree": true, "nonclustered": true}
+	// postgresCastRegexp match casts of postgres default values, e.g. `'a'::character varying`
+	postgresCastRegexp = regexp.MustCompile(`::[a-zA-Z ]+(\[\])?$`)
+	goNameRegexp       = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// goTypeOfColumn return Go type of column, primary keys of integers are unsigned
+func goTypeOfColumn(column ColumnType) reflect.Type {
+	switch normalizeColumnType(column.DatabaseType) {
+	case "boolean":
+		return reflect.TypeOf(false)
+	case "tinyint":
+		return reflect.TypeOf(int8(0))
+	case "smallint", "int2", "smallserial":
+		return reflect.TypeOf(int16(0))
+	case "integer", "mediumint":
+		if column.PrimaryKey {
+			return reflect.TypeOf(uint(0))
+		}
+		return reflect.TypeOf(int(0))
+	case "bigint":
+		if column.PrimaryKey {
+			return reflect.TypeOf(uint64(0))
+		}
+		return reflect.TypeOf(int64(0))
+	case "real", "float4":
+		return reflect.TypeOf(float32(0))
+	case "float", "double", "numeric", "money":
+		return reflect.TypeOf(float64(0))
+	case "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "timestamp", "timestamp with time zone", "timestamp without time zone":
+		return reflect.TypeOf(time.Time{})
+	case "blob", "tinyblob", "mediumblob", "longblob", "bytea", "binary", "varbinary", "image":
+		return reflect.TypeOf([]byte{})
+	}
+	return reflect.TypeOf("")
+}
+
+func goTypeName(typ reflect.Type) string {
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+		return "[]byte"
+	}
+	return typ.String()
+}
+
+// defaultValueOfColumn return default value of column for tag `default`, sequences of primary keys and null are skipped
+func defaultValueOfColumn(dialectName string, column ColumnType, goType reflect.Type) (string, bool) {
+	value := strings.TrimSpace(column.Default.String)
+	if !column.Default.Valid || column.PrimaryKey || value == "" || strings.ToUpper(value) == "NULL" || strings.HasPrefix(value, "nextval(") {
+		return "", false
+	}
+
+	switch dialectName {
+	case "postgres":
+		value = postgresCastRegexp.ReplaceAllString(value, "")
+	case "mssql":
+		for strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			value = value[1 : len(value)-1]
+		}
+	case "mysql":
+		// mysql returns string default values without quotes
+		if goType.Kind() == reflect.Ptr {
+			goType = goType.Elem()
+		}
+		if goType.Kind() == reflect.String && !strings.HasPrefix(value, "'") {
+			value = quoteSQLString(dialectName, value)
+		}
+	}
+	return value, true
+}
+
+// toGoName convert a database name to an exported Go name, e.g. `user_id` to `UserID`
+func toGoName(name string) string {
+	var result string
+	for _, word := range strings.Split(goNameRegexp.ReplaceAllString(name, "_"), "_") {
+		if word == "" {
+			continue
+		}
+
+		if upper := strings.ToUpper(word); strInSlice(upper, commonInitialisms) {
+			result += upper
+		} else {
+			result += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	if result != "" && result[0] >= '0' && result[0] <= '9' {
+		result = "Column" + result
+	}
+	return result
+}
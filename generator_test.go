@@ -0,0 +1,149 @@
+package gorm_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GenUser struct {
+	ID        uint
+	Name      string  `gorm:"size:100;not null;index:idx_gen_users_name"`
+	Email     *string `gorm:"size:200;unique_index:idx_gen_users_email"`
+	Age       int     `gorm:"default:18"`
+	Bio       string  `gorm:"type:text"`
+	CreatedAt time.Time
+	Languages []GenLanguage `gorm:"many2many:gen_user_languages"`
+}
+
+type GenLanguage struct {
+	ID   uint
+	Code string `gorm:"size:8;unique"`
+}
+
+func TestGenerateModels(t *testing.T) {
+	DB.DropTableIfExists(&GenUser{}, &GenLanguage{}, "gen_user_languages")
+	DB.AutoMigrate(&GenUser{}, &GenLanguage{})
+
+	tables := []string{"gen_users", "gen_languages", "gen_user_languages"}
+	isSqlite := os.Getenv("GORM_DIALECT") == "" || os.Getenv("GORM_DIALECT") == "sqlite"
+	if isSqlite {
+		DB.Exec("DROP TABLE IF EXISTS tbl_member")
+		DB.Exec(`CREATE TABLE tbl_member (member_no integer primary key, userName varchar(50) NOT NULL DEFAULT 'anonymous', score real, payload blob)`)
+		defer DB.Exec("DROP TABLE tbl_member")
+		tables = append(tables, "tbl_member")
+	}
+
+	source, err := gorm.GenerateModels(DB, gorm.GenerateOptions{Tables: tables})
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %v", err)
+	}
+
+	for _, fragment := range []string{
+		"type GenUser struct", "type GenLanguage struct", "size:100", "index:idx_gen_users_name", "unique_index:idx_gen_users_email",
+		`size:8;unique"`, "GenLanguages []GenLanguage", "GenUsers []GenUser", "many2many:gen_user_languages",
+	} {
+		if !strings.Contains(string(source), fragment) {
+			t.Errorf("Generated models should contain %v, but got\n%v", fragment, string(source))
+		}
+	}
+
+	if !isSqlite {
+		return
+	}
+
+	expected := `package models
+
+import "time"
+
+// GenUser model of table gen_users
+type GenUser struct {
+	ID           uint
+	Name         string  ` + "`" + `gorm:"not null;size:100;index:idx_gen_users_name"` + "`" + `
+	Email        *string ` + "`" + `gorm:"size:200;unique_index:idx_gen_users_email"` + "`" + `
+	Age          *int    ` + "`" + `gorm:"default:18"` + "`" + `
+	Bio          *string ` + "`" + `gorm:"type:text"` + "`" + `
+	CreatedAt    *time.Time
+	GenLanguages []GenLanguage ` + "`" + `gorm:"many2many:gen_user_languages;jointable_foreignkey:gen_user_id;association_jointable_foreignkey:gen_language_id"` + "`" + `
+}
+
+// GenLanguage model of table gen_languages
+type GenLanguage struct {
+	ID       uint
+	Code     *string   ` + "`" + `gorm:"size:8;unique"` + "`" + `
+	GenUsers []GenUser ` + "`" + `gorm:"many2many:gen_user_languages;jointable_foreignkey:gen_language_id;association_jointable_foreignkey:gen_user_id"` + "`" + `
+}
+
+// TblMember model of table tbl_member
+type TblMember struct {
+	MemberNo uint   ` + "`" + `gorm:"primary_key"` + "`" + `
+	UserName string ` + "`" + `gorm:"column:userName;not null;default:'anonymous';size:50"` + "`" + `
+	Score    *float32
+	Payload  []byte
+}
+
+// TableName return table name of TblMember
+func (TblMember) TableName() string {
+	return "tbl_member"
+}
+`
+	if string(source) != expected {
+		t.Errorf("Should generate models, expects\n%v\nbut got\n%v", expected, string(source))
+	}
+}
+
+func TestGenerateModelsEscapeTags(t *testing.T) {
+	dialect := os.Getenv("GORM_DIALECT")
+	isSqlite := dialect == "" || dialect == "sqlite"
+
+	// backslashes are escape characters in mysql string literals
+	title := `say "hi" \`
+	if dialect == "mysql" {
+		title = `say "hi"`
+	}
+
+	DB.Exec("DROP TABLE IF EXISTS gen_escapes")
+	DB.Exec(`CREATE TABLE gen_escapes (id integer primary key, title varchar(20) NOT NULL DEFAULT '` + title + `', note varchar(20) DEFAULT 'a;b', code integer)`)
+	if dialect != "mysql" {
+		DB.Exec(`CREATE INDEX idx_gen_escapes_code ON gen_escapes(code) WHERE code IN (1, 2)`)
+	}
+	defer DB.Exec("DROP TABLE gen_escapes")
+
+	source, err := gorm.GenerateModels(DB, gorm.GenerateOptions{Tables: []string{"gen_escapes"}})
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %v", err)
+	}
+
+	var tag string
+	for _, line := range strings.Split(string(source), "\n") {
+		if fields := strings.Fields(line); len(fields) > 2 && fields[0] == "Title" {
+			tag = strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]+" "+fields[1])), "`")
+		}
+	}
+
+	modelType := reflect.StructOf([]reflect.StructField{{Name: "Title", Type: reflect.TypeOf(""), Tag: reflect.StructTag(tag)}})
+	field := DB.NewScope(reflect.New(modelType).Interface()).GetModelStruct().StructFields[0]
+	if !strings.Contains(field.TagSettings["DEFAULT"], title) || field.TagSettings["NOT NULL"] == "" {
+		t.Errorf("Generated tags should be parsed to the column definition, but got %v from %v", field.TagSettings, tag)
+	}
+
+	if !strings.Contains(string(source), "a;b") || !strings.Contains(string(source), "is dropped") {
+		t.Errorf("Should drop tags which can't be expressed in struct tags with comments, but got\n%v", string(source))
+	}
+
+	if !isSqlite {
+		return
+	}
+
+	if field.TagSettings["DEFAULT"] != `'say "hi" \'` {
+		t.Errorf("Default value should be kept as is, but got %v", field.TagSettings["DEFAULT"])
+	}
+
+	if !strings.Contains(string(source), `// "default:'a;b'" is dropped`) || !strings.Contains(string(source), "// index idx_gen_escapes_code is dropped") {
+		t.Errorf("Should drop tags which can't be expressed in struct tags with comments, but got\n%v", string(source))
+	}
+}
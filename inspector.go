@@ -0,0 +1,43 @@
+package gorm
+
+// Inspector inspect schema of the current database, tables could be given by name or model
+//     inspector := gorm.NewInspector(db)
+//     tables, err := inspector.Tables()
+//     columns, err := inspector.ColumnTypes(&User{})
+//     indexes, err := inspector.Indexes("users")
+//     foreignKeys, err := inspector.ForeignKeys(&User{})
+type Inspector struct {
+	db Repository
+}
+
+// NewInspector create an Inspector inspecting the database of db
+func NewInspector(db Repository) *Inspector {
+	return &Inspector{db: db}
+}
+
+// Tables return tables of the current database, ordered by name
+func (inspector *Inspector) Tables() ([]Table, error) {
+	return inspector.db.Dialect().Tables()
+}
+
+// ColumnTypes return columns of a table, in order of definition
+func (inspector *Inspector) ColumnTypes(value interface{}) ([]ColumnType, error) {
+	return inspector.db.Dialect().ColumnTypes(inspector.tableName(value))
+}
+
+// Indexes return indexes of a table except the primary key, ordered by name
+func (inspector *Inspector) Indexes(value interface{}) ([]Index, error) {
+	return inspector.db.Dialect().Indexes(inspector.tableName(value))
+}
+
+// ForeignKeys return foreign key constraints of a table
+func (inspector *Inspector) ForeignKeys(value interface{}) ([]ForeignKey, error) {
+	return inspector.db.Dialect().ForeignKeys(inspector.tableName(value))
+}
+
+func (inspector *Inspector) tableName(value interface{}) string {
+	if name, ok := value.(string); ok {
+		return name
+	}
+	return inspector.db.NewScope(value).TableName()
+}
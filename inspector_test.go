@@ -0,0 +1,161 @@
+package gorm_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type InspectorBook struct {
+	ID        uint
+	Title     string `gorm:"size:200;not null;index:idx_inspector_books_title,sort:desc"`
+	ISBN      string `gorm:"unique_index:idx_inspector_books_isbn,where:deleted = 0"`
+	AuthorID  uint
+	Published bool
+	Deleted   int
+}
+
+type InspectorAuthor struct {
+	ID    uint
+	Name  string `gorm:"size:100;index:idx_inspector_authors_name"`
+	Email string `gorm:"size:100;unique_index:idx_inspector_authors_email"`
+}
+
+func TestInspectorOfDialect(t *testing.T) {
+	DB.Exec("DROP TABLE IF EXISTS inspector_articles")
+	DB.DropTableIfExists(&InspectorAuthor{})
+	if err := DB.AutoMigrate(&InspectorAuthor{}).Error(); err != nil {
+		t.Fatalf("No error should happen when migrate, but got %v", err)
+	}
+
+	// foreign keys referencing unsigned primary keys need the same type in mysql
+	authorIDType := "integer"
+	if DB.Dialect().GetName() == "mysql" {
+		authorIDType = "int unsigned"
+	}
+	if err := DB.Exec(`CREATE TABLE inspector_articles (id integer primary key, author_id ` + authorIDType + `,
+		CONSTRAINT fk_articles_author FOREIGN KEY (author_id) REFERENCES inspector_authors (id))`).Error(); err != nil {
+		t.Fatalf("No error should happen when create table, but got %v", err)
+	}
+	defer DB.Exec("DROP TABLE inspector_articles")
+
+	inspector := gorm.NewInspector(DB)
+	tables, err := inspector.Tables()
+	if err != nil {
+		t.Fatalf("No error should happen when inspect tables, but got %v", err)
+	}
+
+	var found int
+	for _, table := range tables {
+		if table.Name == "inspector_authors" || table.Name == "inspector_articles" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Should find inspected tables, but got %v", tables)
+	}
+
+	columnTypes, err := inspector.ColumnTypes(&InspectorAuthor{})
+	var columns []string
+	for _, columnType := range columnTypes {
+		columns = append(columns, columnType.Name)
+	}
+	if err != nil || !reflect.DeepEqual(columns, []string{"id", "name", "email"}) || !columnTypes[0].PrimaryKey {
+		t.Errorf("Should inspect columns of model, but got %+v, %v", columnTypes, err)
+	}
+
+	indexes, err := inspector.Indexes(&InspectorAuthor{})
+	if err != nil {
+		t.Fatalf("No error should happen when inspect indexes, but got %v", err)
+	}
+
+	inspected := map[string]gorm.Index{}
+	for _, index := range indexes {
+		inspected[index.Name] = index
+	}
+	if index := inspected["idx_inspector_authors_name"]; index.Unique || len(index.Columns) != 1 || index.Columns[0].Name != "name" {
+		t.Errorf("Should inspect index idx_inspector_authors_name, but got %+v", indexes)
+	}
+	if index := inspected["idx_inspector_authors_email"]; !index.Unique || len(index.Columns) != 1 || index.Columns[0].Name != "email" {
+		t.Errorf("Should inspect unique index idx_inspector_authors_email, but got %+v", indexes)
+	}
+
+	foreignKeys, err := inspector.ForeignKeys("inspector_articles")
+	if err != nil || len(foreignKeys) != 1 || !reflect.DeepEqual(foreignKeys[0].Columns, []string{"author_id"}) ||
+		foreignKeys[0].ReferencedTable != "inspector_authors" || !reflect.DeepEqual(foreignKeys[0].ReferencedColumns, []string{"id"}) {
+		t.Errorf("Should inspect foreign keys, but got %+v, %v", foreignKeys, err)
+	}
+}
+
+func TestInspector(t *testing.T) {
+	if dialect := os.Getenv("GORM_DIALECT"); dialect != "" && dialect != "sqlite" {
+		t.Skip("exact inspected schema differs by dialect, checked by TestInspectorOfDialect")
+	}
+
+	DB.Exec("DROP TABLE IF EXISTS inspector_reviews")
+	DB.DropTableIfExists(&InspectorBook{})
+	if err := DB.AutoMigrate(&InspectorBook{}).Error(); err != nil {
+		t.Fatalf("No error should happen when migrate, but got %v", err)
+	}
+	DB.Exec(`CREATE TABLE inspector_reviews (id integer primary key, book_id integer, book_title varchar(200),
+		CONSTRAINT fk_reviews_book FOREIGN KEY (book_id, book_title) REFERENCES inspector_books (id, title) ON DELETE CASCADE)`)
+
+	inspector := gorm.NewInspector(DB)
+
+	tables, err := inspector.Tables()
+	if err != nil {
+		t.Fatalf("No error should happen when inspect tables, but got %v", err)
+	}
+
+	var found int
+	for _, table := range tables {
+		if table.Name == "inspector_books" || table.Name == "inspector_reviews" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Should find inspected tables, but got %v", tables)
+	}
+
+	columnTypes, err := inspector.ColumnTypes(&InspectorBook{})
+	if err != nil || len(columnTypes) != 6 || columnTypes[1].Name != "title" || columnTypes[1].Size != 200 || columnTypes[1].Nullable {
+		t.Errorf("Should inspect columns of model, but got %+v, %v", columnTypes, err)
+	}
+
+	indexes, err := inspector.Indexes(&InspectorBook{})
+	if err != nil {
+		t.Fatalf("No error should happen when inspect indexes, but got %v", err)
+	}
+
+	expectedIndexes := []gorm.Index{
+		{Name: "idx_inspector_books_isbn", Unique: true, Columns: []gorm.IndexColumn{{Name: "isbn"}}, Where: "deleted = 0"},
+		{Name: "idx_inspector_books_title", Columns: []gorm.IndexColumn{{Name: "title", Sort: "DESC"}}},
+	}
+	if !reflect.DeepEqual(indexes, expectedIndexes) {
+		t.Errorf("Should inspect indexes, expects %+v, but got %+v", expectedIndexes, indexes)
+	}
+
+	foreignKeys, err := inspector.ForeignKeys("inspector_reviews")
+	if err != nil {
+		t.Fatalf("No error should happen when inspect foreign keys, but got %v", err)
+	}
+
+	expectedForeignKeys := []gorm.ForeignKey{{
+		Columns:           []string{"book_id", "book_title"},
+		ReferencedTable:   "inspector_books",
+		ReferencedColumns: []string{"id", "title"},
+		OnDelete:          "CASCADE",
+		OnUpdate:          "NO ACTION",
+	}}
+	if !reflect.DeepEqual(foreignKeys, expectedForeignKeys) {
+		t.Errorf("Should inspect foreign keys, expects %+v, but got %+v", expectedForeignKeys, foreignKeys)
+	}
+
+	if foreignKeys, err := inspector.ForeignKeys(&InspectorBook{}); err != nil || len(foreignKeys) != 0 {
+		t.Errorf("Should find no foreign keys, but got %+v, %v", foreignKeys, err)
+	}
+
+	DB.Exec("DROP TABLE inspector_reviews")
+}
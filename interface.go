@@ -1,6 +1,9 @@
 package gorm
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 // SQLCommon is the minimal database connection functionality gorm requires.  Implemented by *sql.DB.
 type SQLCommon interface {
@@ -10,10 +13,22 @@ type SQLCommon interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
+// SQLCommonContext is the context aware sibling of SQLCommon, gorm will prefer it when the connection implements it.  Implemented by *sql.DB and *sql.Tx.
+type SQLCommonContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type sqlDb interface {
 	Begin() (*sql.Tx, error)
 }
 
+type sqlDbContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
 type sqlTx interface {
 	Commit() error
 	Rollback() error
@@ -1,22 +1,19 @@
 package gorm
 
 import (
-	"database/sql/driver"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"reflect"
-	"regexp"
 	"strconv"
+	"sync"
 	"time"
 	"unicode"
 )
 
-var (
-	defaultLogger            = logger{log.New(os.Stdout, "\r\n", 0)}
-	sqlRegexp                = regexp.MustCompile(`\?`)
-	numericPlaceHolderRegexp = regexp.MustCompile(`\$\d+`)
-)
+var defaultLogger = logger{log.New(os.Stdout, "\r\n", 0)}
 
 func isPrintable(s string) bool {
 	for _, r := range s {
@@ -30,11 +27,9 @@ func isPrintable(s string) bool {
 var LogFormatter = func(values ...interface{}) (messages []interface{}) {
 	if len(values) > 1 {
 		var (
-			sql             string
-			formattedValues []string
-			level           = values[0]
-			currentTime     = "\n\033[33m[" + NowFunc().Format("2006-01-02 15:04:05") + "]\033[0m"
-			source          = fmt.Sprintf("\033[35m(%v)\033[0m", values[1])
+			level       = values[0]
+			currentTime = "\n\033[33m[" + NowFunc().Format("2006-01-02 15:04:05") + "]\033[0m"
+			source      = fmt.Sprintf("\033[35m(%v)\033[0m", values[1])
 		)
 
 		messages = []interface{}{source, currentTime}
@@ -43,51 +38,7 @@ var LogFormatter = func(values ...interface{}) (messages []interface{}) {
 			// duration
 			messages = append(messages, fmt.Sprintf(" \033[36;1m[%.2fms]\033[0m ", float64(values[2].(time.Duration).Nanoseconds()/1e4)/100.0))
 			// sql
-
-			for _, value := range values[4].([]interface{}) {
-				indirectValue := reflect.Indirect(reflect.ValueOf(value))
-				if indirectValue.IsValid() {
-					value = indirectValue.Interface()
-					if t, ok := value.(time.Time); ok {
-						formattedValues = append(formattedValues, fmt.Sprintf("'%v'", t.Format("2006-01-02 15:04:05")))
-					} else if b, ok := value.([]byte); ok {
-						if str := string(b); isPrintable(str) {
-							formattedValues = append(formattedValues, fmt.Sprintf("'%v'", str))
-						} else {
-							formattedValues = append(formattedValues, "'<binary>'")
-						}
-					} else if r, ok := value.(driver.Valuer); ok {
-						if value, err := r.Value(); err == nil && value != nil {
-							formattedValues = append(formattedValues, fmt.Sprintf("'%v'", value))
-						} else {
-							formattedValues = append(formattedValues, "NULL")
-						}
-					} else {
-						formattedValues = append(formattedValues, fmt.Sprintf("'%v'", value))
-					}
-				} else {
-					formattedValues = append(formattedValues, "NULL")
-				}
-			}
-
-			// differentiate between $n placeholders or else treat like ?
-			if numericPlaceHolderRegexp.MatchString(values[3].(string)) {
-				sql = values[3].(string)
-				for index, value := range formattedValues {
-					placeholder := fmt.Sprintf(`\$%d([^\d]|$)`, index+1)
-					sql = regexp.MustCompile(placeholder).ReplaceAllString(sql, value+"$1")
-				}
-			} else {
-				formattedValuesLength := len(formattedValues)
-				for index, value := range sqlRegexp.Split(values[3].(string), -1) {
-					sql += value
-					if index < formattedValuesLength {
-						sql += formattedValues[index]
-					}
-				}
-			}
-
-			messages = append(messages, sql)
+			messages = append(messages, explainSQL(nil, values[3].(string), values[4].([]interface{})...))
 			messages = append(messages, fmt.Sprintf(" \n\033[36;31m[%v]\033[0m ", strconv.FormatInt(values[5].(int64), 10)+" rows affected or returned "))
 		} else {
 			messages = append(messages, "\033[31;1m")
@@ -117,3 +68,179 @@ type logger struct {
 func (logger logger) Print(values ...interface{}) {
 	logger.Println(LogFormatter(values...)...)
 }
+
+// LogLevel log level of a ContextLogger
+type LogLevel int
+
+const (
+	// LogSilent print nothing
+	LogSilent LogLevel = iota + 1
+	// LogError print errors
+	LogError
+	// LogWarn print errors and slow SQL
+	LogWarn
+	// LogInfo print errors and all SQL
+	LogInfo
+)
+
+// String return the name of level, e.g. `warn`
+func (level LogLevel) String() string {
+	switch level {
+	case LogSilent:
+		return "silent"
+	case LogError:
+		return "error"
+	case LogWarn:
+		return "warn"
+	case LogInfo:
+		return "info"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(level))
+}
+
+// LoggerConfig config of loggers created by `NewLegacyLogger`, `NewJSONLogger`
+type LoggerConfig struct {
+	// SlowThreshold SQL taking longer than it will be logged as a warning, zero to disable
+	SlowThreshold time.Duration
+	// LogLevel default to `LogWarn`
+	LogLevel LogLevel
+}
+
+// DefaultLoggerConfig config of the default logger
+var DefaultLoggerConfig = LoggerConfig{SlowThreshold: 200 * time.Millisecond, LogLevel: LogWarn}
+
+// ContextLogger leveled logger, receives the context of current db, set it with `SetContextLogger`
+type ContextLogger interface {
+	// LogMode return a logger printing logs of level or higher severity
+	LogMode(level LogLevel) ContextLogger
+	Info(ctx context.Context, msg string, data ...interface{})
+	Warn(ctx context.Context, msg string, data ...interface{})
+	Error(ctx context.Context, msg string, data ...interface{})
+	// Trace log executed SQL, fc is only called if the SQL will be logged
+	Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error)
+}
+
+// logEntry a log passed from leveledLogger to its output
+type logEntry struct {
+	Time     time.Time
+	Level    LogLevel
+	Source   string
+	Message  string
+	SQL      string
+	Duration time.Duration
+	Rows     int64
+	Err      error
+	traced   bool
+}
+
+// leveledLogger filter logs by level and slow threshold, then pass them to output
+type leveledLogger struct {
+	LoggerConfig
+	output func(ctx context.Context, entry logEntry)
+}
+
+func newLeveledLogger(config LoggerConfig, output func(ctx context.Context, entry logEntry)) leveledLogger {
+	if config.LogLevel == 0 {
+		config.LogLevel = LogWarn
+	}
+	return leveledLogger{LoggerConfig: config, output: output}
+}
+
+func (l leveledLogger) LogMode(level LogLevel) ContextLogger {
+	l.LogLevel = level
+	return l
+}
+
+func (l leveledLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, LogInfo, msg, data...)
+}
+
+func (l leveledLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, LogWarn, msg, data...)
+}
+
+func (l leveledLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx, LogError, msg, data...)
+}
+
+func (l leveledLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	entry := logEntry{Time: NowFunc(), Duration: NowFunc().Sub(begin), Err: err, traced: true}
+	switch {
+	case err != nil && err != ErrRecordNotFound && l.LogLevel >= LogError:
+		entry.Level = LogError
+	case l.SlowThreshold > 0 && entry.Duration > l.SlowThreshold && l.LogLevel >= LogWarn:
+		entry.Level = LogWarn
+		entry.Message = fmt.Sprintf("slow sql >= %v", l.SlowThreshold)
+	case l.LogLevel >= LogInfo:
+		entry.Level = LogInfo
+	default:
+		return
+	}
+
+	entry.Source = fileWithLineNum()
+	entry.SQL, entry.Rows = fc()
+	l.output(ctx, entry)
+}
+
+func (l leveledLogger) log(ctx context.Context, level LogLevel, msg string, data ...interface{}) {
+	if l.LogLevel >= level {
+		l.output(ctx, logEntry{Time: NowFunc(), Level: level, Source: fileWithLineNum(), Message: fmt.Sprintf(msg, data...)})
+	}
+}
+
+// NewLegacyLogger adapt a `Logger` to ContextLogger, logs are passed to its `Print` in the format of `LogFormatter`
+func NewLegacyLogger(logger Logger, config LoggerConfig) ContextLogger {
+	return newLeveledLogger(config, func(ctx context.Context, entry logEntry) {
+		if !entry.traced {
+			logger.Print("log", entry.Source, entry.Message)
+			return
+		}
+
+		if entry.Message != "" {
+			logger.Print("log", entry.Source, entry.Message)
+		}
+		if entry.Err != nil {
+			logger.Print("log", entry.Source, entry.Err)
+		}
+		logger.Print("sql", entry.Source, entry.Duration, entry.SQL, []interface{}{}, entry.Rows)
+	})
+}
+
+// NewJSONLogger create a ContextLogger writing every log as a line of JSON object to writer
+//     {"time":"2019-01-02T15:04:05.123+08:00","level":"warn","source":"main.go:12","msg":"slow sql >= 200ms","sql":"SELECT * FROM \"users\"","duration_ms":230.5,"rows":10}
+func NewJSONLogger(writer io.Writer, config LoggerConfig) ContextLogger {
+	var mu sync.Mutex
+	return newLeveledLogger(config, func(ctx context.Context, entry logEntry) {
+		record := jsonLogRecord{
+			Time:    entry.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+			Level:   entry.Level.String(),
+			Source:  entry.Source,
+			Message: entry.Message,
+			SQL:     entry.SQL,
+		}
+		if entry.traced {
+			duration := float64(entry.Duration.Nanoseconds()) / 1e6
+			record.Duration, record.Rows = &duration, &entry.Rows
+		}
+		if entry.Err != nil {
+			record.Error = entry.Err.Error()
+		}
+
+		if line, err := json.Marshal(record); err == nil {
+			mu.Lock()
+			writer.Write(append(line, '\n'))
+			mu.Unlock()
+		}
+	})
+}
+
+type jsonLogRecord struct {
+	Time     string   `json:"time"`
+	Level    string   `json:"level"`
+	Source   string   `json:"source,omitempty"`
+	Message  string   `json:"msg,omitempty"`
+	SQL      string   `json:"sql,omitempty"`
+	Duration *float64 `json:"duration_ms,omitempty"`
+	Rows     *int64   `json:"rows,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
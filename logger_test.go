@@ -0,0 +1,85 @@
+package gorm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type printedLogger struct {
+	values [][]interface{}
+}
+
+func (l *printedLogger) Print(values ...interface{}) {
+	l.values = append(l.values, values)
+}
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	db := DB.New().SetContextLogger(gorm.NewJSONLogger(&buf, gorm.LoggerConfig{SlowThreshold: time.Nanosecond}))
+
+	db.LogMode(false).Where("name = ?", "json_logger").First(&User{})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Should log the slow query only, but got %v", lines)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Log should be a JSON object, but got %v", lines[0])
+	}
+
+	if record["level"] != "warn" || !strings.Contains(record["sql"].(string), "'json_logger'") || record["duration_ms"] == nil {
+		t.Errorf("Should log slow query as a warning with rendered SQL, but got %v", record)
+	}
+
+	buf.Reset()
+	db.SetContextLogger(gorm.NewJSONLogger(&buf, gorm.LoggerConfig{LogLevel: gorm.LogWarn}))
+	db.Where("name = ?", "json_logger").First(&User{})
+	if buf.Len() != 0 {
+		t.Errorf("Should not log fast queries at warn level, but got %v", buf.String())
+	}
+
+	db.Exec("SELECT * FROM missing_table")
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"level":"error"`) || !strings.Contains(lines[0], "missing_table") {
+		t.Errorf("Should log failed statement once with its SQL at error level, but got %v", buf.String())
+	}
+
+	buf.Reset()
+	db.BlockGlobalUpdate(true).Delete(&User{})
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"level":"error"`) || !strings.Contains(lines[0], "Missing WHERE clause") {
+		t.Errorf("Should log errors happened without executing statement, but got %v", buf.String())
+	}
+
+	buf.Reset()
+	db.SetContextLogger(gorm.NewJSONLogger(&buf, gorm.LoggerConfig{LogLevel: gorm.LogSilent}))
+	db.Exec("SELECT * FROM missing_table")
+	if buf.Len() != 0 {
+		t.Errorf("Should log nothing when silent, but got %v", buf.String())
+	}
+}
+
+func TestLegacyLogger(t *testing.T) {
+	logger := &printedLogger{}
+	db := DB.New().SetLogger(logger).LogMode(true)
+	db.Where("name = ?", "legacy_logger").First(&User{})
+
+	if len(logger.values) == 0 || logger.values[len(logger.values)-1][0] != "sql" {
+		t.Fatalf("Should print SQL in the format of LogFormatter, but got %v", logger.values)
+	}
+
+	if sql := logger.values[len(logger.values)-1][3].(string); !strings.Contains(sql, "'legacy_logger'") {
+		t.Errorf("Should print rendered SQL, but got %v", sql)
+	}
+
+	logger.values = nil
+	err := db.Exec("SELECT * FROM missing_table").Error()
+	if len(logger.values) != 2 || logger.values[0][0] != "log" || fmt.Sprint(logger.values[0][2]) != fmt.Sprint(err) || logger.values[1][0] != "sql" {
+		t.Errorf("Should print the error of failed statement before its SQL, but got %v", logger.values)
+	}
+}
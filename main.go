@@ -1,11 +1,13 @@
 package gorm
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,10 +16,13 @@ type Repository interface {
 	AddForeignKey(field string, dest string, onDelete string, onUpdate string) Repository
 	AddIndex(indexName string, columns ...string) Repository
 	AddUniqueIndex(indexName string, columns ...string) Repository
+	CreateIndex(index Index) Repository
+	AlterColumnNullability(column string, nullable bool) Repository
 	Assign(attrs ...interface{}) Repository
 	Association(column string) *Association
 	Attrs(attrs ...interface{}) Repository
 	AutoMigrate(values ...interface{}) Repository
+	WithAutoMigrateOptions(options AutoMigrateOptions) Repository
 	Begin() Repository
 	BlockGlobalUpdate(enable bool) Repository
 	Callback() *Callback
@@ -26,6 +31,7 @@ type Repository interface {
 	CommonDB() SQLCommon
 	Count(value interface{}) Repository
 	Create(value interface{}) Repository
+	CreateInBatches(values interface{}, batchSize int) Repository
 	CreateTable(models ...interface{}) Repository
 	SqlDB() *sql.DB
 	Debug() Repository
@@ -34,6 +40,9 @@ type Repository interface {
 	DropColumn(column string) Repository
 	DropTable(values ...interface{}) Repository
 	DropTableIfExists(values ...interface{}) Repository
+	DryRun(enable bool) Repository
+	DryRunStatements() []Statement
+	MigrationPlan(values ...interface{}) ([]string, error)
 	Exec(sql string, values ...interface{}) Repository
 	Find(out interface{}, where ...interface{}) Repository
 	First(out interface{}, where ...interface{}) Repository
@@ -47,6 +56,8 @@ type Repository interface {
 	Having(query interface{}, values ...interface{}) Repository
 	InstantSet(name string, value interface{}) Repository
 	Joins(query string, args ...interface{}) Repository
+	InnerJoins(relationship string, args ...interface{}) Repository
+	LeftJoins(relationship string, args ...interface{}) Repository
 	Last(out interface{}, where ...interface{}) Repository
 	Limit(limit interface{}) Repository
 	LogMode(enable bool) Repository
@@ -58,6 +69,7 @@ type Repository interface {
 	Not(query interface{}, args ...interface{}) Repository
 	Offset(offset interface{}) Repository
 	Omit(columns ...string) Repository
+	OnConflict(conflict Conflict) Repository
 	Or(query interface{}, args ...interface{}) Repository
 	Order(value interface{}, reorder ...bool) Repository
 	Pluck(column string, value interface{}) Repository
@@ -68,10 +80,14 @@ type Repository interface {
 	Related(value interface{}, foreignKeys ...string) Repository
 	RemoveForeignKey(field string, dest string) Repository
 	RemoveIndex(indexName string) Repository
+	RenameColumn(oldName string, newName string) Repository
+	RenameTable(oldName string, newName string) Repository
 	Rollback() Repository
+	RollbackTo(name string) Repository
 	Row() *sql.Row
 	Rows() (*sql.Rows, error)
 	Save(value interface{}) Repository
+	SavePoint(name string) Repository
 	Scan(dest interface{}) Repository
 	ScanRows(rows *sql.Rows, result interface{}) error
 	Scopes(funcs ...func(Repository) Repository) Repository
@@ -79,16 +95,21 @@ type Repository interface {
 	Set(name string, value interface{}) Repository
 	SetJoinTableHandler(source interface{}, column string, handler JoinTableHandlerInterface)
 	SetLogger(log Logger) Repository
+	SetContextLogger(logger ContextLogger) Repository
+	ContextLogger() ContextLogger
 	SingularTable(enable bool)
 	SubQuery() *Expression
 	Table(name string) Repository
 	Take(out interface{}, where ...interface{}) Repository
+	ToSQL(fc func(tx Repository) Repository) string
+	Transaction(fc func(tx Repository) error) error
 	Unscoped() Repository
 	Update(attrs ...interface{}) Repository
 	UpdateColumn(attrs ...interface{}) Repository
 	UpdateColumns(values interface{}) Repository
 	Updates(values interface{}, ignoreProtectedAttrs ...bool) Repository
 	Where(query interface{}, args ...interface{}) Repository
+	WithContext(ctx context.Context) Repository
 	Value() interface{}
 	SetValue(v interface{}) Repository
 	Error() error
@@ -112,6 +133,7 @@ type Repository interface {
 	Print(v ...interface{})
 	Values() map[string]interface{}
 	SetValues(vals map[string]interface{}) Repository
+	Context() context.Context
 }
 
 
@@ -124,10 +146,11 @@ type repository struct {
 	// single db
 	db                SQLCommon
 	blockGlobalUpdate bool
-	logMode           int
-	logger            Logger
+	logLevel          LogLevel
+	logger            ContextLogger
 	search            *Search
 	values            map[string]interface{}
+	ctx               context.Context
 
 	// global db
 	parent        Repository
@@ -241,19 +264,34 @@ func (r *repository) Callback() *Callback {
 	return r.parent.Callbacks()
 }
 
-// SetLogger replace default logger
+// SetLogger replace default logger, it is adapted to a ContextLogger by `NewLegacyLogger`
 func (r *repository) SetLogger(log Logger) Repository {
-	r.logger = log
+	r.logger = NewLegacyLogger(log, DefaultLoggerConfig)
+	if r.logLevel != 0 {
+		r.logger = r.logger.LogMode(r.logLevel)
+	}
+	return r
+}
+
+// SetContextLogger replace default logger with a leveled logger
+//     db.SetContextLogger(gorm.NewJSONLogger(os.Stdout, gorm.LoggerConfig{SlowThreshold: time.Second, LogLevel: gorm.LogWarn}))
+func (r *repository) SetContextLogger(logger ContextLogger) Repository {
+	r.logger = logger
 	return r
 }
 
-// LogMode set log mode, `true` for detailed logs, `false` for no log, default, will only print error logs
+// ContextLogger return current leveled logger
+func (r *repository) ContextLogger() ContextLogger {
+	return r.logger
+}
+
+// LogMode set log mode, `true` for all logs, `false` for errors and slow SQL only, default
 func (r *repository) LogMode(enable bool) Repository {
+	r.logLevel = LogWarn
 	if enable {
-		r.logMode = 2
-	} else {
-		r.logMode = 1
+		r.logLevel = LogInfo
 	}
+	r.logger = r.logger.LogMode(r.logLevel)
 	return r
 }
 
@@ -301,7 +339,7 @@ func (r *repository) SubQuery() *Expression {
 	return Expr(fmt.Sprintf("(%v)", scope.SQL), scope.SQLVars...)
 }
 
-// Where return a new relation, filter records with given conditions, accepts `map`, `struct` or `string` as conditions, refer http://jinzhu.github.io/gorm/crud.html#query
+// Where return a new relation, filter records with given conditions, accepts `map`, `struct`, `string` or groups like `gorm.Or(...)` as conditions, refer http://jinzhu.github.io/gorm/crud.html#query
 func (r *repository) Where(query interface{}, args ...interface{}) Repository {
 	return r.Clone().Search().Where(query, args...).db
 }
@@ -345,6 +383,13 @@ func (r *repository) Omit(columns ...string) Repository {
 	return r.Clone().Search().Omit(columns...).db
 }
 
+// OnConflict specify how to resolve conflicts with existing records when creating
+//     db.OnConflict(Conflict{Columns: []string{"email"}, DoUpdates: []string{"name", "updated_at"}}).Create(&user)
+//     db.OnConflict(Conflict{DoNothing: true}).Create(&users)
+func (r *repository) OnConflict(conflict Conflict) Repository {
+	return r.Set("gorm:on_conflict", conflict)
+}
+
 // Group specify the group method on the find
 func (r *repository) Group(query string) Repository {
 	return r.Clone().Search().Group(query).db
@@ -355,12 +400,28 @@ func (r *repository) Having(query interface{}, values ...interface{}) Repository
 	return r.Clone().Search().Having(query, values...).db
 }
 
-// Joins specify Joins conditions
+// Joins specify Joins conditions, a relationship's field name is joined with `LEFT JOIN`, refer `LeftJoins`
 //     db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
+//     db.Joins("Company").Find(&users)
 func (r *repository) Joins(query string, args ...interface{}) Repository {
 	return r.Clone().Search().Joins(query, args...).db
 }
 
+// InnerJoins join the table of a relationship with `INNER JOIN`, refer `LeftJoins`
+func (r *repository) InnerJoins(relationship string, args ...interface{}) Repository {
+	return r.Clone().Search().relationshipJoins("INNER JOIN", relationship, args...).db
+}
+
+// LeftJoins join the table of a relationship by its field name with `LEFT JOIN`, the ON clause is built from foreign keys of the
+// relationship, the join table of many2many and the type of polymorphic relationships; the joined table is aliased as the field name,
+// and columns of belongs to and has one relationships are selected into the field unless selects are specified; args are additional
+// conditions of the ON clause on the joined table, similar to `Where`
+//     db.LeftJoins("Company").Find(&users)
+//     db.InnerJoins("Company", gorm.Col("name").Eq("jinzhu")).Where(`"Company"."id" > ?`, 10).Find(&users)
+func (r *repository) LeftJoins(relationship string, args ...interface{}) Repository {
+	return r.Clone().Search().relationshipJoins("LEFT JOIN", relationship, args...).db
+}
+
 // Scopes pass current database connection to arguments `func(Repository) Repository`, which could be used to add conditions dynamically
 //     func AmountGreaterThan1000(db Repository) Repository {
 //         return db.Where("amount > ?", 1000)
@@ -496,7 +557,7 @@ func (r *repository) FirstOrCreate(out interface{}, where ...interface{}) Reposi
 		if !result.RecordNotFound() {
 			return result
 		}
-		return c.NewScope(out).inlineCondition(where...).initialize().callCallbacks(c.Parent().Callbacks().creates).db
+		return c.NewScope(out).inlineCondition(where...).initialize().rootConflict().callCallbacks(c.Parent().Callbacks().creates).db
 	} else if len(c.Search().assignAttrs) > 0 {
 		return c.NewScope(out).InstanceSet("gorm:update_interface", c.Search().assignAttrs).callCallbacks(c.Parent().Callbacks().updates).db
 	}
@@ -532,7 +593,7 @@ func (r *repository) UpdateColumns(values interface{}) Repository {
 
 // Save update value in database, if the value doesn't have primary key, will insert it
 func (r *repository) Save(value interface{}) Repository {
-	scope := r.NewScope(value)
+	scope := r.NewScope(value).rootConflict()
 	if !scope.PrimaryKeyZero() {
 		newDB := scope.callCallbacks(r.parent.Callbacks().updates).db
 		if newDB.Error() == nil && newDB.RowsAffected() == 0 {
@@ -546,7 +607,39 @@ func (r *repository) Save(value interface{}) Repository {
 // Create insert the value into database
 func (r *repository) Create(value interface{}) Repository {
 	scope := r.NewScope(value)
-	return scope.callCallbacks(r.parent.Callbacks().creates).db
+	return scope.rootConflict().callCallbacks(r.parent.Callbacks().creates).db
+}
+
+// CreateInBatches insert a slice of values with multi-row INSERT statements, each containing at most batchSize rows,
+// all batches are inserted in one transaction
+//     db.CreateInBatches(&users, 1000)
+func (r *repository) CreateInBatches(values interface{}, batchSize int) Repository {
+	reflectValue := indirect(reflect.ValueOf(values))
+	if reflectValue.Kind() != reflect.Slice || batchSize <= 0 || reflectValue.Len() <= batchSize {
+		return r.Create(values)
+	}
+
+	var rowsAffected int64
+	err := r.Transaction(func(tx Repository) error {
+		for i := 0; i < reflectValue.Len(); i += batchSize {
+			end := i + batchSize
+			if end > reflectValue.Len() {
+				end = reflectValue.Len()
+			}
+
+			result := tx.Create(reflectValue.Slice(i, end).Interface())
+			if err := result.Error(); err != nil {
+				return err
+			}
+			rowsAffected += result.RowsAffected()
+		}
+		return nil
+	})
+
+	clone := r.Clone()
+	clone.AddError(err)
+	clone.SetRowsAffected(rowsAffected)
+	return clone
 }
 
 // Delete delete value match given conditions, if the value has primary key, then will including the primary key as condition
@@ -588,16 +681,36 @@ func (r *repository) Table(name string) Repository {
 	return clone
 }
 
+// WithContext return a new relation bound to ctx, queries and statements executed with it will be cancelled when ctx is done
+//     db.WithContext(ctx).Where("name = ?", "jinzhu").First(&user)
+func (r *repository) WithContext(ctx context.Context) Repository {
+	c := r.Clone().(*repository)
+	c.ctx = ctx
+	return c
+}
+
 // Debug start debug mode
 func (r *repository) Debug() Repository {
 	return r.Clone().LogMode(true)
 }
 
-// Begin begin a transaction
+// Begin begin a transaction, the transaction will be bound to the context given by `WithContext` if any
 func (r *repository) Begin() Repository {
 	c := r.Clone()
+	if _, ok := c.SQLCommonDB().(dryRunDB); ok {
+		return c
+	}
+
 	if db, ok := c.SQLCommonDB().(sqlDb); ok && db != nil {
-		tx, err := db.Begin()
+		var (
+			tx  *sql.Tx
+			err error
+		)
+		if dbCtx, ok := db.(sqlDbContext); ok && r.ctx != nil {
+			tx, err = dbCtx.BeginTx(r.ctx, nil)
+		} else {
+			tx, err = db.Begin()
+		}
 		c.SetSQLCommonDB(interface{}(tx).(SQLCommon))
 
 		c.Dialect().SetDB(c.SQLCommonDB())
@@ -611,7 +724,9 @@ func (r *repository) Begin() Repository {
 // Commit commit a transaction
 func (r *repository) Commit() Repository {
 	var emptySQLTx *sql.Tx
-	if db, ok := r.db.(sqlTx); ok && db != nil && db != emptySQLTx {
+	if _, ok := r.db.(dryRunDB); ok {
+		return r
+	} else if db, ok := r.db.(sqlTx); ok && db != nil && db != emptySQLTx {
 		r.AddError(db.Commit())
 	} else {
 		r.AddError(ErrInvalidTransaction)
@@ -622,7 +737,9 @@ func (r *repository) Commit() Repository {
 // Rollback rollback a transaction
 func (r *repository) Rollback() Repository {
 	var emptySQLTx *sql.Tx
-	if db, ok := r.db.(sqlTx); ok && db != nil && db != emptySQLTx {
+	if _, ok := r.db.(dryRunDB); ok {
+		return r
+	} else if db, ok := r.db.(sqlTx); ok && db != nil && db != emptySQLTx {
 		r.AddError(db.Rollback())
 	} else {
 		r.AddError(ErrInvalidTransaction)
@@ -630,6 +747,153 @@ func (r *repository) Rollback() Repository {
 	return r
 }
 
+// SavePoint set a savepoint with name inside current transaction
+func (r *repository) SavePoint(name string) Repository {
+	return r.Exec(r.Dialect().SavePointSQL(name))
+}
+
+// RollbackTo rollback current transaction to the savepoint with name
+func (r *repository) RollbackTo(name string) Repository {
+	return r.Exec(r.Dialect().RollbackToSavePointSQL(name))
+}
+
+// DryRun enable dry run mode to build statements without executing them, built statements are returned by `DryRunStatements`,
+// every `DryRun(true)` starts a new recording; in dry run mode, queries find no records and dialects find no tables, columns or indexes
+//     tx := db.DryRun(true)
+//     tx.AutoMigrate(&User{})
+//     for _, statement := range tx.DryRunStatements() {
+//         fmt.Println(statement.Explained)
+//     }
+func (r *repository) DryRun(enable bool) Repository {
+	clone := r.Clone()
+	recorder, running := r.values["gorm:dry_run"].(*dryRunRecorder)
+
+	var db SQLCommon
+	if enable {
+		db = r.db
+		if running {
+			db = recorder.db
+		}
+		recorder = &dryRunRecorder{dialect: r.dialect, db: db}
+		clone.Values()["gorm:dry_run"] = recorder
+		db = dryRunDB{recorder: recorder}
+	} else if running {
+		delete(clone.Values(), "gorm:dry_run")
+		db = recorder.db
+	} else {
+		return clone
+	}
+
+	clone.SetSQLCommonDB(db)
+	clone.Dialect().SetDB(db)
+	return clone
+}
+
+// DryRunStatements return statements built since dry run mode was enabled
+func (r *repository) DryRunStatements() []Statement {
+	if recorder, ok := r.values["gorm:dry_run"].(*dryRunRecorder); ok {
+		return recorder.recorded()
+	}
+	return nil
+}
+
+// MigrationPlan return DDL statements `AutoMigrate` would run for given models in order, without changing the database;
+// unlike dry run mode, existing tables, columns and indexes are inspected in the database
+//     statements, err := db.MigrationPlan(&User{}, &Product{})
+func (r *repository) MigrationPlan(values ...interface{}) ([]string, error) {
+	tx := r.DryRun(true)
+	if recorder, ok := tx.Values()["gorm:dry_run"].(*dryRunRecorder); ok {
+		recorder.inspect = true
+	}
+
+	if err := tx.AutoMigrate(values...).Error(); err != nil {
+		return nil, err
+	}
+
+	var (
+		statements []string
+		planned    = map[string]bool{}
+	)
+	for _, statement := range tx.DryRunStatements() {
+		// models sharing a join table would create it more than once, as the database is unchanged
+		if !planned[statement.Explained] {
+			planned[statement.Explained] = true
+			statements = append(statements, statement.Explained)
+		}
+	}
+	return statements, nil
+}
+
+// ToSQL return statements fc would run with vars rendered, separated by `;\n`, statements are built but not executed
+//     sql := db.ToSQL(func(tx gorm.Repository) gorm.Repository {
+//         return tx.Model(&User{}).Where("name = ?", "jinzhu").Limit(10).Find(&users)
+//     })
+func (r *repository) ToSQL(fc func(tx Repository) Repository) string {
+	tx := r.DryRun(true)
+	fc(tx)
+
+	var statements []string
+	for _, statement := range tx.DryRunStatements() {
+		statements = append(statements, statement.Explained)
+	}
+	return strings.Join(statements, ";\n")
+}
+
+var savePointSequence uint64
+
+// Transaction run fc inside a transaction, commit it if fc returns nil, and rollback it if fc returns an error or panics (the panic will be re-raised)
+// If current db is already a transaction, a savepoint will be used so only changes made by fc are rolled back
+//     db.Transaction(func(tx gorm.Repository) error {
+//         if err := tx.Create(&user).Error(); err != nil {
+//             return err
+//         }
+//         return tx.Transaction(func(tx gorm.Repository) error {
+//             return tx.Create(&order).Error()
+//         })
+//     })
+func (r *repository) Transaction(fc func(tx Repository) error) (err error) {
+	return transaction(r, r.inTransaction(), fc)
+}
+
+// transaction run fc inside a transaction begun from db, or inside a savepoint if db is already a transaction
+func transaction(db Repository, inTransaction bool, fc func(tx Repository) error) (err error) {
+	panicked := true
+
+	if inTransaction {
+		savePoint := fmt.Sprintf("gorm_savepoint_%d", atomic.AddUint64(&savePointSequence, 1))
+		if err = db.SavePoint(savePoint).Error(); err != nil {
+			return err
+		}
+
+		defer func() {
+			if panicked || err != nil {
+				db.RollbackTo(savePoint)
+			}
+		}()
+
+		err = fc(db)
+		panicked = false
+		return err
+	}
+
+	tx := db.Begin()
+	if err = tx.Error(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if panicked || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fc(tx); err == nil {
+		err = tx.Commit().Error()
+	}
+	panicked = false
+	return err
+}
+
 // NewRecord check if value's primary key is blank
 func (r *repository) NewRecord(value interface{}) bool {
 	return r.NewScope(value).PrimaryKeyZero()
@@ -645,13 +909,13 @@ func (r *repository) RecordNotFound() bool {
 	return false
 }
 
-// CreateTable create table for models
+// CreateTable create table for models, foreign key constraints are added after all tables are created, refer `AutoMigrate`
 func (r *repository) CreateTable(models ...interface{}) Repository {
 	db := r.Unscoped()
 	for _, model := range models {
 		db = db.NewScope(model).createTable().db
 	}
-	return db
+	return autoForeignKeys(db, models)
 }
 
 // DropTable drop table for models
@@ -696,13 +960,33 @@ func (r *repository) HasTable(value interface{}) bool {
 	return has
 }
 
-// AutoMigrate run auto migration for given models, will only add missing fields, won't delete/change current data
+// AutoMigrateOptions options of `AutoMigrate`, set by `WithAutoMigrateOptions`
+//     db.WithAutoMigrateOptions(gorm.AutoMigrateOptions{ModifyColumns: true}).AutoMigrate(&User{})
+type AutoMigrateOptions struct {
+	// ModifyColumns modify existing columns whose type, size or nullability differ from models, same as `gorm:auto_migrate_modify_columns`
+	ModifyColumns bool
+	// Destructive apply modifications which may lose data or fail with existing data, e.g. changing type, shrinking size, adding `NOT NULL`,
+	// same as `gorm:auto_migrate_destructive`
+	Destructive bool
+}
+
+// AutoMigrate run auto migration for given models, will only add missing fields, won't delete/change current data,
+// unless modifying columns whose type, size or nullability differ from models is enabled, destructive changes need another flag:
+//     db.WithAutoMigrateOptions(gorm.AutoMigrateOptions{ModifyColumns: true, Destructive: true}).AutoMigrate(&User{})
+// Foreign key constraints of relationships are added after all tables are migrated, actions are specified with tag `constraint`,
+// e.g. `gorm:"constraint:OnDelete:CASCADE,OnUpdate:SET NULL"`, disable them with:
+//     db.Set("gorm:auto_foreign_keys", false).AutoMigrate(&User{})
 func (r *repository) AutoMigrate(values ...interface{}) Repository {
 	db := r.Unscoped()
 	for _, value := range values {
 		db = db.NewScope(value).autoMigrate().db
 	}
-	return db
+	return autoForeignKeys(db, values)
+}
+
+// WithAutoMigrateOptions set options of `AutoMigrate` and `MigrationPlan`
+func (r *repository) WithAutoMigrateOptions(options AutoMigrateOptions) Repository {
+	return r.Set("gorm:auto_migrate_modify_columns", options.ModifyColumns).Set("gorm:auto_migrate_destructive", options.Destructive)
 }
 
 // ModifyColumn modify column to type
@@ -712,6 +996,31 @@ func (r *repository) ModifyColumn(column string, typ string) Repository {
 	return scope.db
 }
 
+// RenameColumn rename a column of model's table
+//     db.Model(&User{}).RenameColumn("name", "full_name")
+func (r *repository) RenameColumn(oldName string, newName string) Repository {
+	scope := r.NewScope(r.value)
+	scope.renameColumn(oldName, newName)
+	return scope.db
+}
+
+// RenameTable rename a table
+//     db.RenameTable("people", "users")
+func (r *repository) RenameTable(oldName string, newName string) Repository {
+	scope := r.NewScope(r.value)
+	scope.renameTable(oldName, newName)
+	return scope.db
+}
+
+// AlterColumnNullability make a column of model's table nullable or not, its definition is built from model's field,
+// sqlite rebuilds the table to change it
+//     db.Model(&User{}).AlterColumnNullability("name", false)
+func (r *repository) AlterColumnNullability(column string, nullable bool) Repository {
+	scope := r.NewScope(r.value)
+	scope.alterColumnNullability(column, nullable)
+	return scope.db
+}
+
 // DropColumn drop a column
 func (r *repository) DropColumn(column string) Repository {
 	scope := r.NewScope(r.value)
@@ -733,6 +1042,14 @@ func (r *repository) AddUniqueIndex(indexName string, columns ...string) Reposit
 	return scope.db
 }
 
+// CreateIndex add index with options, e.g. a partial index of columns in specified order:
+//     db.Model(&User{}).CreateIndex(gorm.Index{Name: "idx_user_name", Columns: []gorm.IndexColumn{{Name: "name"}, {Name: "age", Sort: "DESC"}}, Where: "deleted_at IS NULL"})
+func (r *repository) CreateIndex(index Index) Repository {
+	scope := r.Unscoped().NewScope(r.value)
+	scope.createIndex(index)
+	return scope.db
+}
+
 // RemoveIndex remove index with name
 func (r *repository) RemoveIndex(indexName string) Repository {
 	scope := r.NewScope(r.value)
@@ -778,8 +1095,9 @@ func (r *repository) Association(column string) *Association {
 	return &Association{err: err}
 }
 
-// Preload preload associations with given conditions
+// Preload preload associations with given conditions, and `PreloadOptions` to order, limit or select preloaded records
 //    db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
+//    db.Preload("Orders", gorm.PreloadOptions{Order: "created_at DESC", Limit: 5}).Find(&users)
 func (r *repository) Preload(column string, conditions ...interface{}) Repository {
 	return r.Clone().Search().Preload(column, conditions...).db
 }
@@ -819,25 +1137,28 @@ func (r *repository) SetJoinTableHandler(source interface{}, column string, hand
 	}
 }
 
-// AddError add error to the db
+// AddError add error to the db and log it, errors of executed statements are logged with their SQL by `ContextLogger.Trace` instead
 func (r *repository) AddError(err error) error {
 	if err != nil {
 		if err != ErrRecordNotFound {
-			if r.logMode == 0 {
-				go r.Print(fileWithLineNum(), err)
-			} else {
-				r.Log(err)
-			}
-
-			errors := Errors(r.GetErrors())
-			errors = errors.Add(err)
-			if len(errors) > 1 {
-				err = errors
-			}
+			r.logger.Error(r.Context(), "%v", err)
 		}
+		return addError(r, err)
+	}
+	return err
+}
 
-		r.SetError(err)
+// addError add error to errors of db without logging it
+func addError(db Repository, err error) error {
+	if err != ErrRecordNotFound {
+		errors := Errors(db.GetErrors())
+		errors = errors.Add(err)
+		if len(errors) > 1 {
+			err = errors
+		}
 	}
+
+	db.SetError(err)
 	return err
 }
 
@@ -937,6 +1258,13 @@ func (r *repository) SetDialect(d Dialect) Repository {
 	return r
 }
 
+func (r *repository) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
 
 ////////////////////////////////////////////////////////////////////////////////
 // Private Methods For DB
@@ -947,12 +1275,13 @@ func (r *repository) Clone() Repository {
 		db:                r.db,
 		parent:            r.parent,
 		logger:            r.logger,
-		logMode:           r.logMode,
+		logLevel:          r.logLevel,
 		values:            map[string]interface{}{},
 		value:             r.value,
 		err:               r.Error(),
 		blockGlobalUpdate: r.blockGlobalUpdate,
 		dialect:           newDialect(r.dialect.GetName(), r.db),
+		ctx:               r.ctx,
 	}
 
 	for key, value := range r.values {
@@ -969,18 +1298,27 @@ func (r *repository) Clone() Repository {
 	return db
 }
 
+func (r *repository) inTransaction() bool {
+	var emptySQLTx *sql.Tx
+	db, ok := r.db.(sqlTx)
+	return ok && db != nil && db != emptySQLTx
+}
+
+// Print log v at info level
 func (r *repository) Print(v ...interface{}) {
-	r.logger.Print(v...)
+	r.logger.Info(r.Context(), "%v", fmt.Sprint(v...))
 }
 
+// Log log v at info level, same as `Print`
 func (r *repository) Log(v ...interface{}) {
-	if r != nil && r.logMode == 2 {
-		r.Print(append([]interface{}{"log", fileWithLineNum()}, v...)...)
+	if r != nil {
+		r.Print(v...)
 	}
 }
 
+// Slog log executed sql, which was started at t
 func (r *repository) Slog(sql string, t time.Time, vars ...interface{}) {
-	if r.logMode == 2 {
-		r.Print("sql", fileWithLineNum(), NowFunc().Sub(t), sql, vars, r.RowsAffected())
-	}
+	r.logger.Trace(r.Context(), t, func() (string, int64) {
+		return explainSQL(r.Dialect(), sql, vars...), r.RowsAffected()
+	}, r.Error())
 }
@@ -1,8 +1,10 @@
 package gorm_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -396,6 +398,84 @@ func TestTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionBlock(t *testing.T) {
+	if err := DB.Transaction(func(tx gorm.Repository) error {
+		return tx.Save(&User{Name: "transaction-block"}).Error()
+	}); err != nil {
+		t.Errorf("No error should raise, but got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", "transaction-block").Error(); err != nil {
+		t.Errorf("Should find record committed by transaction block")
+	}
+
+	rollbackErr := errors.New("rollback")
+	if err := DB.Transaction(func(tx gorm.Repository) error {
+		tx.Save(&User{Name: "transaction-block-rollback"})
+		return rollbackErr
+	}); err != rollbackErr {
+		t.Errorf("Should return the error of the block, but got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", "transaction-block-rollback").Error(); err == nil {
+		t.Errorf("Should not find record after the block returned an error")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Should re-raise panic of the block")
+			}
+		}()
+		DB.Transaction(func(tx gorm.Repository) error {
+			tx.Save(&User{Name: "transaction-block-panic"})
+			panic("transaction-block-panic")
+		})
+	}()
+
+	if err := DB.First(&User{}, "name = ?", "transaction-block-panic").Error(); err == nil {
+		t.Errorf("Should not find record after the block panicked")
+	}
+}
+
+func TestNestedTransactionBlock(t *testing.T) {
+	err := DB.Transaction(func(tx gorm.Repository) error {
+		if err := tx.Save(&User{Name: "nested-transaction-outer"}).Error(); err != nil {
+			return err
+		}
+
+		if err := tx.Transaction(func(tx gorm.Repository) error {
+			tx.Save(&User{Name: "nested-transaction-inner"})
+			return errors.New("rollback inner")
+		}); err == nil {
+			t.Errorf("Should return the error of the nested block")
+		}
+
+		if err := tx.First(&User{}, "name = ?", "nested-transaction-inner").Error(); err == nil {
+			t.Errorf("Should not find record after rolled back to savepoint")
+		}
+
+		return tx.Transaction(func(tx gorm.Repository) error {
+			return tx.Save(&User{Name: "nested-transaction-inner-2"}).Error()
+		})
+	})
+	if err != nil {
+		t.Errorf("No error should raise, but got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", "nested-transaction-outer").Error(); err != nil {
+		t.Errorf("Should find record of the outer block")
+	}
+
+	if err := DB.First(&User{}, "name = ?", "nested-transaction-inner").Error(); err == nil {
+		t.Errorf("Should not find record of the rolled back nested block")
+	}
+
+	if err := DB.First(&User{}, "name = ?", "nested-transaction-inner-2").Error(); err != nil {
+		t.Errorf("Should find record of the committed nested block")
+	}
+}
+
 func TestRow(t *testing.T) {
 	user1 := User{Name: "RowUser1", Age: 1, Birthday: parseTime("2000-1-1")}
 	user2 := User{Name: "RowUser2", Age: 10, Birthday: parseTime("2010-1-1")}
@@ -600,6 +680,58 @@ func TestJoinsWithSelect(t *testing.T) {
 	}
 }
 
+func TestRelationshipJoins(t *testing.T) {
+	user := User{
+		Name:           "relationship_joins",
+		Company:        Company{Name: "relationship_joins_company"},
+		BillingAddress: Address{Address1: "billing address"},
+		CreditCard:     CreditCard{Number: "433333333333"},
+		Emails:         []Email{{Email: "relationship_join1@example.com"}, {Email: "relationship_join2@example.com"}},
+		Languages:      []Language{{Name: "relationship_joins_language"}},
+	}
+	DB.Save(&user)
+	DB.Save(&User{Name: "relationship_joins"})
+
+	var users []User
+	if err := DB.Joins("Company").Joins("BillingAddress").InnerJoins("CreditCard").Where(gorm.Col("name").Eq("relationship_joins")).Find(&users).Error(); err != nil {
+		t.Fatalf("No error should happen when join relationships, but got %v", err)
+	}
+	if len(users) != 1 || users[0].Company.Name != user.Company.Name || users[0].BillingAddress.Address1 != "billing address" || users[0].CreditCard.Number != "433333333333" {
+		t.Errorf("Should scan joined relationships into fields, but got %+v", users)
+	}
+
+	DB.LeftJoins("Company").Where(gorm.Col("name").Eq("relationship_joins")).Order("users.id").Find(&users)
+	if len(users) != 2 || users[1].Company.Name != "" || users[1].Company.Id != 0 {
+		t.Errorf("Should keep fields of unmatched left joined relationships empty, but got %+v", users)
+	}
+
+	DB.InnerJoins("Emails", gorm.Col("email").Eq("relationship_join2@example.com")).Where(gorm.Col("name").Eq("relationship_joins")).Find(&users)
+	if len(users) != 1 || users[0].Id != user.Id {
+		t.Errorf("Should join has many relationship with conditions, but got %+v", users)
+	}
+
+	DB.InnerJoins("Languages").Where(gorm.Col("Languages.name").Eq("relationship_joins_language")).Find(&users)
+	if len(users) != 1 || users[0].Id != user.Id {
+		t.Errorf("Should join many2many relationship, but got %+v", users)
+	}
+
+	if err := DB.InnerJoins("Unknown").Find(&users).Error(); err == nil {
+		t.Errorf("Should return error when join unknown relationship")
+	}
+
+	cat := Cat{Name: "relationship_joins_cat", Toy: Toy{Name: "cat toy"}}
+	dog := Dog{Id: cat.Id, Name: "relationship_joins_dog", Toys: []Toy{{Name: "dog toy"}}}
+	DB.Save(&cat)
+	dog.Id = cat.Id
+	DB.Save(&dog)
+
+	var cats []Cat
+	DB.InnerJoins("Toy").Where(gorm.Col("name").Eq("relationship_joins_cat")).Find(&cats)
+	if len(cats) != 1 || cats[0].Toy.Name != "cat toy" {
+		t.Errorf("Should join polymorphic relationship with type condition, but got %+v", cats)
+	}
+}
+
 func TestHaving(t *testing.T) {
 	rows, err := DB.Select("name, count(*) as total").Table("users").Group("name").Having("name IN (?)", []string{"2", "3"}).Rows()
 
@@ -917,6 +1049,42 @@ func TestBlockGlobalUpdate(t *testing.T) {
 	}
 }
 
+func TestWithContext(t *testing.T) {
+	user := User{Name: "context_user", Age: 1}
+	if err := DB.WithContext(context.Background()).Save(&user).Error(); err != nil {
+		t.Errorf("No error should happen when saving with a live context, but got %v", err)
+	}
+
+	if DB.WithContext(context.Background()).Context() == nil {
+		t.Errorf("Context should be carried by the returned db")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var users []User
+	if err := DB.WithContext(ctx).Where("name = ?", "context_user").Find(&users).Error(); err == nil {
+		t.Errorf("Should got error when querying with a cancelled context")
+	}
+
+	if err := DB.WithContext(ctx).Create(&User{Name: "context_user_2"}).Error(); err == nil {
+		t.Errorf("Should got error when creating with a cancelled context")
+	}
+
+	if err := DB.WithContext(ctx).Model(&user).Update("age", 2).Error(); err == nil {
+		t.Errorf("Should got error when updating with a cancelled context")
+	}
+
+	if !DB.Where("name = ?", "context_user_2").First(&User{}).RecordNotFound() {
+		t.Errorf("Record should not be created with a cancelled context")
+	}
+
+	fake := &gorm.FakeRepository{}
+	if fake.WithContext(ctx).Context() != ctx {
+		t.Errorf("FakeRepository should record the context it was given")
+	}
+}
+
 func BenchmarkGorm(b *testing.B) {
 	b.N = 2000
 	for x := 0; x < b.N; x++ {
@@ -964,3 +1132,34 @@ func parseTime(str string) *time.Time {
 	t := now.New(time.Now().UTC()).MustParse(str)
 	return &t
 }
+
+func TestToSQL(t *testing.T) {
+	sql := DB.ToSQL(func(tx gorm.Repository) gorm.Repository {
+		return tx.Model(&User{}).Where("name = ?", "to_sql").Limit(10).Find(&[]User{})
+	})
+
+	if !strings.Contains(sql, "'to_sql'") || !strings.Contains(sql, "LIMIT 10") {
+		t.Errorf("Should return rendered query, but got %v", sql)
+	}
+
+	sql = DB.ToSQL(func(tx gorm.Repository) gorm.Repository {
+		return tx.Create(&User{Name: "to_sql"})
+	})
+
+	if !strings.HasPrefix(sql, "INSERT INTO") {
+		t.Errorf("Should return rendered insert statement, but got %v", sql)
+	}
+
+	var count int
+	if DB.Model(&User{}).Where("name = ?", "to_sql").Count(&count); count != 0 {
+		t.Errorf("Statements should not be executed")
+	}
+
+	sql = DB.ToSQL(func(tx gorm.Repository) gorm.Repository {
+		return tx.Model(&User{}).Where("name = ?", "to_sql").Count(&count)
+	})
+
+	if !strings.Contains(sql, "count(*)") {
+		t.Errorf("Should return rendered count query, but got %v", sql)
+	}
+}
@@ -0,0 +1,1282 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// MemoryRepository is a Repository keeping records in memory, rows are stored per table with the model's struct metadata,
+// it is intended for tests that need stateful persistence without a database
+//     db := gorm.NewMemoryRepository()
+//     db.Create(&User{Name: "jinzhu"})
+//     db.Where("name = ?", "jinzhu").First(&user)
+// Tables are created on first write. Only simple conditions are supported, joins, raw SQL, associations and callbacks are not
+type MemoryRepository struct {
+	value        interface{}
+	err          error
+	rowsAffected int64
+
+	// single db
+	blockGlobalUpdate bool
+	logLevel          LogLevel
+	logger            ContextLogger
+	search            *Search
+	values            map[string]interface{}
+	ctx               context.Context
+	tx                *memoryTransaction
+
+	// global db
+	parent        Repository
+	callbacks     *Callback
+	dialect       Dialect
+	singularTable bool
+	store         *memoryStore
+}
+
+var _ Repository = &MemoryRepository{}
+
+// NewMemoryRepository create a MemoryRepository with an empty store
+func NewMemoryRepository() *MemoryRepository {
+	db := &MemoryRepository{
+		logger:    NewLegacyLogger(defaultLogger, DefaultLoggerConfig),
+		values:    map[string]interface{}{},
+		callbacks: DefaultCallback,
+		dialect:   &commonDialect{},
+		store:     &memoryStore{tables: map[string]*memoryTable{}},
+	}
+	db.parent = db
+	return db
+}
+
+// New clone a new db connection without search conditions
+func (r *MemoryRepository) New() Repository {
+	clone := r.Clone()
+	clone.SetSearch(nil)
+	clone.SetValue(nil)
+	return clone
+}
+
+// Close close current db connection, it does nothing for MemoryRepository
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+// SqlDB always return nil as there is no database connection
+func (r *MemoryRepository) SqlDB() *sql.DB {
+	return nil
+}
+
+// CommonDB always return nil as there is no database connection
+func (r *MemoryRepository) CommonDB() SQLCommon {
+	return nil
+}
+
+// Dialect get dialect
+func (r *MemoryRepository) Dialect() Dialect {
+	return r.dialect
+}
+
+// Callback return `Callbacks` container, callbacks are not called by MemoryRepository
+func (r *MemoryRepository) Callback() *Callback {
+	r.parent.SetCallbacks(r.parent.Callbacks().clone())
+	return r.parent.Callbacks()
+}
+
+// SetLogger replace default logger, it is adapted to a ContextLogger by `NewLegacyLogger`
+func (r *MemoryRepository) SetLogger(log Logger) Repository {
+	r.logger = NewLegacyLogger(log, DefaultLoggerConfig)
+	if r.logLevel != 0 {
+		r.logger = r.logger.LogMode(r.logLevel)
+	}
+	return r
+}
+
+// SetContextLogger replace default logger with a leveled logger
+//     db.SetContextLogger(gorm.NewJSONLogger(os.Stdout, gorm.LoggerConfig{SlowThreshold: time.Second, LogLevel: gorm.LogWarn}))
+func (r *MemoryRepository) SetContextLogger(logger ContextLogger) Repository {
+	r.logger = logger
+	return r
+}
+
+// ContextLogger return current leveled logger
+func (r *MemoryRepository) ContextLogger() ContextLogger {
+	return r.logger
+}
+
+// LogMode set log mode, `true` for all logs, `false` for errors and slow SQL only, default
+func (r *MemoryRepository) LogMode(enable bool) Repository {
+	r.logLevel = LogWarn
+	if enable {
+		r.logLevel = LogInfo
+	}
+	if r.logger != nil {
+		r.logger = r.logger.LogMode(r.logLevel)
+	}
+	return r
+}
+
+// BlockGlobalUpdate if true, generates an error on update/delete without where clause.
+func (r *MemoryRepository) BlockGlobalUpdate(enable bool) Repository {
+	r.blockGlobalUpdate = enable
+	return r
+}
+
+// HasBlockGlobalUpdate return state of block
+func (r *MemoryRepository) HasBlockGlobalUpdate() bool {
+	return r.blockGlobalUpdate
+}
+
+// SingularTable use singular table by default
+func (r *MemoryRepository) SingularTable(enable bool) {
+	r.parent.SetIsSingularTable(enable)
+}
+
+// NewScope create a scope for current operation
+func (r *MemoryRepository) NewScope(value interface{}) *Scope {
+	dbClone := r.Clone()
+	dbClone.SetValue(value)
+	return &Scope{db: dbClone, Search: dbClone.Search().clone(), Value: value}
+}
+
+// QueryExpr returns the query as expr object
+func (r *MemoryRepository) QueryExpr() *Expression {
+	scope := r.NewScope(r.value)
+	scope.InstanceSet("skip_bindvar", true)
+	scope.prepareQuerySQL()
+
+	return Expr(scope.SQL, scope.SQLVars...)
+}
+
+// SubQuery returns the query as sub query
+func (r *MemoryRepository) SubQuery() *Expression {
+	scope := r.NewScope(r.value)
+	scope.InstanceSet("skip_bindvar", true)
+	scope.prepareQuerySQL()
+
+	return Expr(fmt.Sprintf("(%v)", scope.SQL), scope.SQLVars...)
+}
+
+// Where return a new relation, filter records with given conditions, accepts `map`, `struct` or `string` as conditions
+func (r *MemoryRepository) Where(query interface{}, args ...interface{}) Repository {
+	return r.Clone().Search().Where(query, args...).db
+}
+
+// Or filter records that match before conditions or this one, similar to `Where`
+func (r *MemoryRepository) Or(query interface{}, args ...interface{}) Repository {
+	return r.Clone().Search().Or(query, args...).db
+}
+
+// Not filter records that don't match current conditions, similar to `Where`
+func (r *MemoryRepository) Not(query interface{}, args ...interface{}) Repository {
+	return r.Clone().Search().Not(query, args...).db
+}
+
+// Limit specify the number of records to be retrieved
+func (r *MemoryRepository) Limit(limit interface{}) Repository {
+	return r.Clone().Search().Limit(limit).db
+}
+
+// Offset specify the number of records to skip before starting to return the records
+func (r *MemoryRepository) Offset(offset interface{}) Repository {
+	return r.Clone().Search().Offset(offset).db
+}
+
+// Order specify order when retrieve records, set reorder to `true` to overwrite defined conditions, only columns with `ASC`/`DESC` are supported
+//     db.Order("name DESC")
+//     db.Order("name DESC", true) // reorder
+func (r *MemoryRepository) Order(value interface{}, reorder ...bool) Repository {
+	return r.Clone().Search().Order(value, reorder...).db
+}
+
+// Select specify fields that you want to save when creating/updating
+func (r *MemoryRepository) Select(query interface{}, args ...interface{}) Repository {
+	return r.Clone().Search().Select(query, args...).db
+}
+
+// Omit specify fields that you want to ignore when saving
+func (r *MemoryRepository) Omit(columns ...string) Repository {
+	return r.Clone().Search().Omit(columns...).db
+}
+
+// OnConflict specify how to resolve conflicts with existing records when creating
+func (r *MemoryRepository) OnConflict(conflict Conflict) Repository {
+	return r.Set("gorm:on_conflict", conflict)
+}
+
+// Group specify the group method on the find, it is ignored by MemoryRepository
+func (r *MemoryRepository) Group(query string) Repository {
+	return r.Clone().Search().Group(query).db
+}
+
+// Having specify HAVING conditions for GROUP BY, it is ignored by MemoryRepository
+func (r *MemoryRepository) Having(query interface{}, values ...interface{}) Repository {
+	return r.Clone().Search().Having(query, values...).db
+}
+
+// Joins specify Joins conditions, joins are not supported by MemoryRepository
+func (r *MemoryRepository) Joins(query string, args ...interface{}) Repository {
+	return r.unsupported("joins")
+}
+
+// InnerJoins join the table of a relationship, joins are not supported by MemoryRepository
+func (r *MemoryRepository) InnerJoins(relationship string, args ...interface{}) Repository {
+	return r.unsupported("joins")
+}
+
+// LeftJoins join the table of a relationship, joins are not supported by MemoryRepository
+func (r *MemoryRepository) LeftJoins(relationship string, args ...interface{}) Repository {
+	return r.unsupported("joins")
+}
+
+// Scopes pass current database connection to arguments `func(Repository) Repository`, which could be used to add conditions dynamically
+func (r *MemoryRepository) Scopes(funcs ...func(Repository) Repository) Repository {
+	var db Repository
+	db = r
+	for _, fn := range funcs {
+		db = fn(db)
+	}
+	return db
+}
+
+// Unscoped return all record including deleted record
+func (r *MemoryRepository) Unscoped() Repository {
+	return r.Clone().Search().unscoped().db
+}
+
+// Attrs initialize struct with argument if record not found with `FirstOrInit` or `FirstOrCreate`
+func (r *MemoryRepository) Attrs(attrs ...interface{}) Repository {
+	return r.Clone().Search().Attrs(attrs...).db
+}
+
+// Assign assign result with argument regardless it is found or not with `FirstOrInit` or `FirstOrCreate`
+func (r *MemoryRepository) Assign(attrs ...interface{}) Repository {
+	return r.Clone().Search().Assign(attrs...).db
+}
+
+// First find first record that match given conditions, order by primary key
+func (r *MemoryRepository) First(out interface{}, where ...interface{}) Repository {
+	newScope := r.NewScope(out)
+	newScope.Search.Limit(1)
+	return r.query(newScope.Set("gorm:order_by_primary_key", "ASC").inlineCondition(where...), out).db
+}
+
+// Take return a record that match given conditions, in insertion order
+func (r *MemoryRepository) Take(out interface{}, where ...interface{}) Repository {
+	newScope := r.NewScope(out)
+	newScope.Search.Limit(1)
+	return r.query(newScope.inlineCondition(where...), out).db
+}
+
+// Last find last record that match given conditions, order by primary key
+func (r *MemoryRepository) Last(out interface{}, where ...interface{}) Repository {
+	newScope := r.NewScope(out)
+	newScope.Search.Limit(1)
+	return r.query(newScope.Set("gorm:order_by_primary_key", "DESC").inlineCondition(where...), out).db
+}
+
+// Find find records that match given conditions
+func (r *MemoryRepository) Find(out interface{}, where ...interface{}) Repository {
+	return r.query(r.NewScope(out).inlineCondition(where...), out).db
+}
+
+// Scan scan records of current model or table to dest
+func (r *MemoryRepository) Scan(dest interface{}) Repository {
+	return r.query(r.NewScope(r.value), dest).db
+}
+
+// Row is not supported by MemoryRepository, always return nil
+func (r *MemoryRepository) Row() *sql.Row {
+	return nil
+}
+
+// Rows is not supported by MemoryRepository
+func (r *MemoryRepository) Rows() (*sql.Rows, error) {
+	return nil, errors.New("gorm: rows are not supported by MemoryRepository")
+}
+
+// ScanRows is not supported by MemoryRepository
+func (r *MemoryRepository) ScanRows(rows *sql.Rows, result interface{}) error {
+	return errors.New("gorm: rows are not supported by MemoryRepository")
+}
+
+// Pluck used to query single column from a model as a map
+//     var ages []int64
+//     db.Find(&users).Pluck("age", &ages)
+func (r *MemoryRepository) Pluck(column string, value interface{}) Repository {
+	scope := r.NewScope(r.value)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	if dest.Kind() != reflect.Slice {
+		scope.Err(fmt.Errorf("results should be a slice, not %s", dest.Kind()))
+		return scope.db
+	}
+
+	rows, err := r.selectRows(scope, true)
+	if scope.Err(err) != nil {
+		return scope.db
+	}
+
+	column = r.columnName(scope, column)
+	results := reflect.MakeSlice(dest.Type(), 0, len(rows))
+	for _, row := range rows {
+		elem := reflect.New(dest.Type().Elem())
+		field := &Field{StructField: &StructField{Name: column, Struct: reflect.StructField{Type: elem.Elem().Type()}}, Field: elem.Elem()}
+		if scope.Err(field.Set(copyMemoryValue(row[column]))) != nil {
+			return scope.db
+		}
+		results = reflect.Append(results, elem.Elem())
+	}
+	dest.Set(results)
+	return scope.db
+}
+
+// Count get how many records for a model
+func (r *MemoryRepository) Count(value interface{}) Repository {
+	scope := r.NewScope(r.value)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rows, err := r.selectRows(scope, false)
+	if scope.Err(err) == nil {
+		field := &Field{StructField: &StructField{Name: "count"}, Field: reflect.Indirect(reflect.ValueOf(value))}
+		scope.Err(field.Set(int64(len(rows))))
+	}
+	return scope.db
+}
+
+// Related is not supported by MemoryRepository
+func (r *MemoryRepository) Related(value interface{}, foreignKeys ...string) Repository {
+	return r.unsupported("associations")
+}
+
+// FirstOrInit find first matched record or initialize a new one with given conditions (only works with struct, map conditions)
+func (r *MemoryRepository) FirstOrInit(out interface{}, where ...interface{}) Repository {
+	c := r.Clone()
+	if result := c.First(out, where...); result.RecordNotFound() {
+		c.NewScope(out).inlineCondition(where...).initialize()
+	} else if result.Error() != nil {
+		return result
+	} else if len(c.Search().assignAttrs) > 0 {
+		c.NewScope(out).updatedAttrsWithValues(c.Search().assignAttrs)
+	}
+	return c
+}
+
+// FirstOrCreate find first matched record or create a new one with given conditions (only works with struct, map conditions)
+func (r *MemoryRepository) FirstOrCreate(out interface{}, where ...interface{}) Repository {
+	c := r.Clone()
+	if result := c.First(out, where...); result.RecordNotFound() {
+		return c.NewScope(out).inlineCondition(where...).initialize().db.Create(out)
+	} else if result.Error() != nil {
+		return result
+	} else if len(c.Search().assignAttrs) > 0 {
+		return c.New().Model(out).Updates(c.Search().assignAttrs)
+	}
+	return c
+}
+
+// Update update attributes and `UpdatedAt`
+func (r *MemoryRepository) Update(attrs ...interface{}) Repository {
+	return r.Updates(toSearchableMap(attrs...), true)
+}
+
+// Updates update attributes and `UpdatedAt`
+func (r *MemoryRepository) Updates(values interface{}, ignoreProtectedAttrs ...bool) Repository {
+	return r.update(r.NewScope(r.value), values, true).db
+}
+
+// UpdateColumn update attributes without updating `UpdatedAt`
+func (r *MemoryRepository) UpdateColumn(attrs ...interface{}) Repository {
+	return r.UpdateColumns(toSearchableMap(attrs...))
+}
+
+// UpdateColumns update attributes without updating `UpdatedAt`
+func (r *MemoryRepository) UpdateColumns(values interface{}) Repository {
+	return r.update(r.NewScope(r.value), values, false).db
+}
+
+// Save update value in store, if the value doesn't have primary key, will insert it
+func (r *MemoryRepository) Save(value interface{}) Repository {
+	scope := r.NewScope(value)
+	if scope.PrimaryKeyZero() {
+		return r.Create(value)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	table := r.table(scope.TableName())
+	if field, ok := scope.FieldByName("UpdatedAt"); ok {
+		scope.Err(field.Set(NowFunc()))
+	}
+
+	row := r.toRow(scope)
+	for idx, stored := range table.rows {
+		if r.samePrimaryKey(scope, stored, row) {
+			if createdAt, ok := scope.FieldByName("CreatedAt"); ok && createdAt.IsBlank {
+				row[createdAt.DBName] = stored[createdAt.DBName]
+				scope.Err(createdAt.Set(stored[createdAt.DBName]))
+			}
+			previous := stored
+			table.rows[idx] = row
+			r.logUndo(func() {
+				if idx := table.indexOf(row); idx >= 0 {
+					table.rows[idx] = previous
+				}
+			})
+			scope.db.SetRowsAffected(1)
+			return scope.db
+		}
+	}
+
+	if scope.Err(r.insert(scope, table)) == nil {
+		scope.db.SetRowsAffected(1)
+	}
+	return scope.db
+}
+
+// Create insert the value into store, value could be a pointer of struct or slice of structs
+func (r *MemoryRepository) Create(value interface{}) Repository {
+	scope := r.NewScope(value)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	table := r.table(scope.TableName())
+	if indirectValue := scope.IndirectValue(); indirectValue.Kind() == reflect.Slice {
+		for i := 0; i < indirectValue.Len(); i++ {
+			elem := indirectValue.Index(i)
+			if elem.Kind() != reflect.Ptr {
+				elem = elem.Addr()
+			}
+			if scope.Err(r.insert(scope.New(elem.Interface()), table)) != nil {
+				break
+			}
+			scope.db.SetRowsAffected(int64(i + 1))
+		}
+	} else if scope.Err(r.insert(scope, table)) == nil {
+		scope.db.SetRowsAffected(1)
+	}
+	return scope.db
+}
+
+// CreateInBatches insert a slice of values, batches make no difference in memory
+func (r *MemoryRepository) CreateInBatches(values interface{}, batchSize int) Repository {
+	return r.Create(values)
+}
+
+// Delete delete value match given conditions, if the value has primary key, then will including the primary key as condition;
+// records having `DeletedAt` field will be soft deleted unless `Unscoped`
+func (r *MemoryRepository) Delete(value interface{}, where ...interface{}) Repository {
+	scope := r.NewScope(value).inlineCondition(where...)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	indexes, hasConditions, err := r.matchRows(scope)
+	if scope.Err(err) != nil {
+		return scope.db
+	}
+
+	if !hasConditions && r.blockGlobalUpdate {
+		scope.Err(errors.New("Missing WHERE clause while deleting"))
+		return scope.db
+	}
+
+	table := r.table(scope.TableName())
+	if deletedAtField, ok := scope.FieldByName("DeletedAt"); ok && !scope.Search.Unscoped {
+		now := NowFunc()
+		for _, idx := range indexes {
+			r.updateRow(table.rows[idx], memoryRow{deletedAtField.DBName: &now})
+		}
+	} else {
+		deleted := map[int]bool{}
+		for _, idx := range indexes {
+			deleted[idx] = true
+		}
+
+		var rows, deletedRows []memoryRow
+		for idx, row := range table.rows {
+			if deleted[idx] {
+				deletedRows = append(deletedRows, row)
+			} else {
+				rows = append(rows, row)
+			}
+		}
+		table.rows = rows
+
+		// deleted rows are restored to their positions
+		sort.Ints(indexes)
+		r.logUndo(func() {
+			for i, row := range deletedRows {
+				idx := indexes[i]
+				if idx > len(table.rows) {
+					idx = len(table.rows)
+				}
+				table.rows = append(table.rows[:idx], append([]memoryRow{row}, table.rows[idx:]...)...)
+			}
+		})
+	}
+
+	scope.db.SetRowsAffected(int64(len(indexes)))
+	return scope.db
+}
+
+// Raw is not supported by MemoryRepository
+func (r *MemoryRepository) Raw(sql string, values ...interface{}) Repository {
+	return r.unsupported("raw SQL")
+}
+
+// Exec is not supported by MemoryRepository
+func (r *MemoryRepository) Exec(sql string, values ...interface{}) Repository {
+	return r.unsupported("raw SQL")
+}
+
+// Model specify the model you would like to run db operations
+//    // update all users's name to `hello`
+//    db.Model(&User{}).Update("name", "hello")
+//    // if user's primary key is non-blank, will use it as condition, then will only update the user's name to `hello`
+//    db.Model(&user).Update("name", "hello")
+func (r *MemoryRepository) Model(value interface{}) Repository {
+	c := r.Clone()
+	c.SetValue(value)
+	return c
+}
+
+// Table specify the table you would like to run db operations
+func (r *MemoryRepository) Table(name string) Repository {
+	clone := r.Clone()
+	clone.Search().Table(name)
+	clone.SetValue(nil)
+	return clone
+}
+
+// WithContext return a new relation bound to ctx, it could be read back with `Context`
+func (r *MemoryRepository) WithContext(ctx context.Context) Repository {
+	c := r.Clone().(*MemoryRepository)
+	c.ctx = ctx
+	return c
+}
+
+// Debug start debug mode
+func (r *MemoryRepository) Debug() Repository {
+	return r.Clone().LogMode(true)
+}
+
+// Begin begin a transaction, changes made by it are reverted by `Rollback`
+func (r *MemoryRepository) Begin() Repository {
+	c := r.Clone().(*MemoryRepository)
+	c.tx = &memoryTransaction{savePoints: map[string]int{}}
+	return c
+}
+
+// Commit commit a transaction
+func (r *MemoryRepository) Commit() Repository {
+	if r.tx == nil || r.tx.done {
+		r.AddError(ErrInvalidTransaction)
+		return r
+	}
+	r.tx.done = true
+	return r
+}
+
+// Rollback rollback a transaction
+func (r *MemoryRepository) Rollback() Repository {
+	if r.tx == nil || r.tx.done {
+		r.AddError(ErrInvalidTransaction)
+		return r
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.tx.rollbackTo(0)
+	r.tx.done = true
+	return r
+}
+
+// SavePoint set a savepoint with name inside current transaction
+func (r *MemoryRepository) SavePoint(name string) Repository {
+	if r.tx == nil || r.tx.done {
+		r.AddError(ErrInvalidTransaction)
+		return r
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.tx.savePoints[name] = len(r.tx.undo)
+	return r
+}
+
+// RollbackTo rollback current transaction to the savepoint with name
+func (r *MemoryRepository) RollbackTo(name string) Repository {
+	if r.tx == nil || r.tx.done {
+		r.AddError(ErrInvalidTransaction)
+		return r
+	}
+
+	savePoint, ok := r.tx.savePoints[name]
+	if !ok {
+		r.AddError(fmt.Errorf("gorm: savepoint %v not found", name))
+		return r
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.tx.rollbackTo(savePoint)
+	return r
+}
+
+// ToSQL returns empty string as MemoryRepository doesn't build SQL, fc isn't called
+func (r *MemoryRepository) ToSQL(fc func(tx Repository) Repository) string {
+	return ""
+}
+
+// Transaction run fc inside a transaction, commit it if fc returns nil, and rollback it if fc returns an error or panics (the panic will be re-raised)
+// If current db is already a transaction, a savepoint will be used so only changes made by fc are rolled back
+func (r *MemoryRepository) Transaction(fc func(tx Repository) error) error {
+	return transaction(r, r.tx != nil && !r.tx.done, fc)
+}
+
+// NewRecord check if value's primary key is blank
+func (r *MemoryRepository) NewRecord(value interface{}) bool {
+	return r.NewScope(value).PrimaryKeyZero()
+}
+
+// RecordNotFound check if returning ErrRecordNotFound error
+func (r *MemoryRepository) RecordNotFound() bool {
+	for _, err := range r.GetErrors() {
+		if err == ErrRecordNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTable create table for models
+func (r *MemoryRepository) CreateTable(models ...interface{}) Repository {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, model := range models {
+		r.table(r.NewScope(model).TableName())
+	}
+	return r
+}
+
+// DropTable drop table for models
+func (r *MemoryRepository) DropTable(values ...interface{}) Repository {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, value := range values {
+		name, ok := value.(string)
+		if !ok {
+			name = r.NewScope(value).TableName()
+		}
+
+		if table, ok := r.store.tables[name]; ok {
+			delete(r.store.tables, name)
+			r.logUndo(func() {
+				if _, ok := r.store.tables[name]; !ok {
+					r.store.tables[name] = table
+				}
+			})
+		}
+	}
+	return r
+}
+
+// DropTableIfExists drop table if it is exist
+func (r *MemoryRepository) DropTableIfExists(values ...interface{}) Repository {
+	return r.DropTable(values...)
+}
+
+// DryRun isn't supported by MemoryRepository, as it doesn't build statements
+func (r *MemoryRepository) DryRun(enable bool) Repository {
+	if enable {
+		return r.unsupported("dry runs")
+	}
+	return r.Clone()
+}
+
+// DryRunStatements return nil as MemoryRepository doesn't build statements
+func (r *MemoryRepository) DryRunStatements() []Statement {
+	return nil
+}
+
+// MigrationPlan return nil as MemoryRepository has no schema to migrate
+func (r *MemoryRepository) MigrationPlan(values ...interface{}) ([]string, error) {
+	return nil, nil
+}
+
+// HasTable check has table or not
+func (r *MemoryRepository) HasTable(value interface{}) bool {
+	name, ok := value.(string)
+	if !ok {
+		name = r.NewScope(value).TableName()
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	_, ok = r.store.tables[name]
+	return ok
+}
+
+// AutoMigrate create tables for given models
+func (r *MemoryRepository) AutoMigrate(values ...interface{}) Repository {
+	return r.CreateTable(values...)
+}
+
+// WithAutoMigrateOptions does nothing as columns are not typed in MemoryRepository
+func (r *MemoryRepository) WithAutoMigrateOptions(options AutoMigrateOptions) Repository {
+	return r
+}
+
+// ModifyColumn does nothing as columns are not typed in MemoryRepository
+func (r *MemoryRepository) ModifyColumn(column string, typ string) Repository {
+	return r
+}
+
+// RenameColumn rename a column of stored rows
+func (r *MemoryRepository) RenameColumn(oldName string, newName string) Repository {
+	scope := r.NewScope(r.value)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if table, ok := r.store.tables[scope.TableName()]; ok {
+		for _, row := range table.rows {
+			if value, ok := row[oldName]; ok {
+				r.updateRow(row, memoryRow{newName: value})
+				delete(row, oldName)
+			}
+		}
+	}
+	return r
+}
+
+// RenameTable rename a table
+func (r *MemoryRepository) RenameTable(oldName string, newName string) Repository {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if table, ok := r.store.tables[oldName]; ok {
+		previous, replaced := r.store.tables[newName]
+		r.store.tables[newName] = table
+		delete(r.store.tables, oldName)
+		r.logUndo(func() {
+			if r.store.tables[newName] == table {
+				delete(r.store.tables, newName)
+				if replaced {
+					r.store.tables[newName] = previous
+				}
+				r.store.tables[oldName] = table
+			}
+		})
+	}
+	return r
+}
+
+// AlterColumnNullability does nothing as columns are not typed in MemoryRepository
+func (r *MemoryRepository) AlterColumnNullability(column string, nullable bool) Repository {
+	return r
+}
+
+// DropColumn drop a column
+func (r *MemoryRepository) DropColumn(column string) Repository {
+	scope := r.NewScope(r.value)
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if table, ok := r.store.tables[scope.TableName()]; ok {
+		for _, row := range table.rows {
+			if _, ok := row[column]; ok {
+				r.updateRow(row, nil)
+				delete(row, column)
+			}
+		}
+	}
+	return r
+}
+
+// AddIndex does nothing for MemoryRepository
+func (r *MemoryRepository) AddIndex(indexName string, columns ...string) Repository {
+	return r
+}
+
+// AddUniqueIndex does nothing for MemoryRepository
+func (r *MemoryRepository) AddUniqueIndex(indexName string, columns ...string) Repository {
+	return r
+}
+
+// CreateIndex does nothing for MemoryRepository
+func (r *MemoryRepository) CreateIndex(index Index) Repository {
+	return r
+}
+
+// RemoveIndex does nothing for MemoryRepository
+func (r *MemoryRepository) RemoveIndex(indexName string) Repository {
+	return r
+}
+
+// AddForeignKey does nothing for MemoryRepository
+func (r *MemoryRepository) AddForeignKey(field string, dest string, onDelete string, onUpdate string) Repository {
+	return r
+}
+
+// RemoveForeignKey does nothing for MemoryRepository
+func (r *MemoryRepository) RemoveForeignKey(field string, dest string) Repository {
+	return r
+}
+
+// Association is not supported by MemoryRepository, the returned association has an error
+func (r *MemoryRepository) Association(column string) *Association {
+	return &Association{column: column, err: errors.New("gorm: associations are not supported by MemoryRepository")}
+}
+
+// Preload is ignored by MemoryRepository
+func (r *MemoryRepository) Preload(column string, conditions ...interface{}) Repository {
+	return r.Clone().Search().Preload(column, conditions...).db
+}
+
+// Set set setting by name, will clone a new db, and update its setting
+func (r *MemoryRepository) Set(name string, value interface{}) Repository {
+	return r.Clone().InstantSet(name, value)
+}
+
+// InstantSet instant set setting, will affect current db
+func (r *MemoryRepository) InstantSet(name string, value interface{}) Repository {
+	r.values[name] = value
+	return r
+}
+
+// Get get setting by name
+func (r *MemoryRepository) Get(name string) (value interface{}, ok bool) {
+	value, ok = r.values[name]
+	return
+}
+
+// SetJoinTableHandler does nothing for MemoryRepository
+func (r *MemoryRepository) SetJoinTableHandler(source interface{}, column string, handler JoinTableHandlerInterface) {
+}
+
+// AddError add error to the db
+func (r *MemoryRepository) AddError(err error) error {
+	if err != nil {
+		if err != ErrRecordNotFound {
+			if r.logger != nil {
+				r.logger.Error(r.Context(), "%v", err)
+			}
+		}
+		return addError(r, err)
+	}
+	return err
+}
+
+// GetErrors get happened errors from the db
+func (r *MemoryRepository) GetErrors() []error {
+	if errs, ok := r.Error().(Errors); ok {
+		return errs
+	} else if r.Error() != nil {
+		return []error{r.Error()}
+	}
+	return []error{}
+}
+
+func (r *MemoryRepository) Value() interface{} {
+	return r.value
+}
+
+func (r *MemoryRepository) SetValue(v interface{}) Repository {
+	r.value = v
+	return r
+}
+
+func (r *MemoryRepository) Error() error {
+	return r.err
+}
+
+func (r *MemoryRepository) SetError(err error) Repository {
+	r.err = err
+	return r
+}
+
+func (r *MemoryRepository) RowsAffected() int64 {
+	return r.rowsAffected
+}
+
+func (r *MemoryRepository) SetRowsAffected(row int64) Repository {
+	r.rowsAffected = row
+	return r
+}
+
+func (r *MemoryRepository) Search() *Search {
+	return r.search
+}
+
+func (r *MemoryRepository) SetSearch(search *Search) Repository {
+	r.search = search
+	return r
+}
+
+func (r *MemoryRepository) Parent() Repository {
+	return r.parent
+}
+
+func (r *MemoryRepository) SetParent(p Repository) Repository {
+	r.parent = p
+	return r
+}
+
+func (r *MemoryRepository) SQLCommonDB() SQLCommon {
+	return nil
+}
+
+func (r *MemoryRepository) SetSQLCommonDB(sc SQLCommon) Repository {
+	return r
+}
+
+func (r *MemoryRepository) Callbacks() *Callback {
+	return r.callbacks
+}
+
+func (r *MemoryRepository) SetCallbacks(cb *Callback) Repository {
+	r.callbacks = cb
+	return r
+}
+
+func (r *MemoryRepository) IsSingularTable() bool {
+	return r.singularTable
+}
+
+func (r *MemoryRepository) SetIsSingularTable(singularTable bool) Repository {
+	r.singularTable = singularTable
+	return r
+}
+
+func (r *MemoryRepository) Values() map[string]interface{} {
+	return r.values
+}
+
+func (r *MemoryRepository) SetValues(vals map[string]interface{}) Repository {
+	r.values = vals
+	return r
+}
+
+func (r *MemoryRepository) SetDialect(d Dialect) Repository {
+	r.dialect = d
+	return r
+}
+
+func (r *MemoryRepository) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Private Methods For DB
+////////////////////////////////////////////////////////////////////////////////
+
+func (r *MemoryRepository) Clone() Repository {
+	db := &MemoryRepository{
+		parent:            r.parent,
+		logger:            r.logger,
+		logLevel:          r.logLevel,
+		values:            map[string]interface{}{},
+		value:             r.value,
+		err:               r.Error(),
+		blockGlobalUpdate: r.blockGlobalUpdate,
+		dialect:           r.dialect,
+		callbacks:         r.callbacks,
+		ctx:               r.ctx,
+		tx:                r.tx,
+		store:             r.store,
+	}
+
+	for key, value := range r.values {
+		db.values[key] = value
+	}
+
+	if r.search == nil {
+		db.search = &Search{limit: -1, offset: -1}
+	} else {
+		db.search = r.Search().clone()
+	}
+
+	db.Search().db = db
+	return db
+}
+
+// Print log v at info level
+func (r *MemoryRepository) Print(v ...interface{}) {
+	if r.logger != nil {
+		r.logger.Info(r.Context(), "%v", fmt.Sprint(v...))
+	}
+}
+
+// Log log v at info level, same as `Print`
+func (r *MemoryRepository) Log(v ...interface{}) {
+	if r != nil {
+		r.Print(v...)
+	}
+}
+
+// Slog log executed sql, which was started at t
+func (r *MemoryRepository) Slog(sql string, t time.Time, vars ...interface{}) {
+	if r.logger != nil {
+		r.logger.Trace(r.Context(), t, func() (string, int64) {
+			return explainSQL(r.Dialect(), sql, vars...), r.RowsAffected()
+		}, r.Error())
+	}
+}
+
+func (r *MemoryRepository) unsupported(feature string) Repository {
+	c := r.Clone()
+	c.AddError(fmt.Errorf("gorm: %v are not supported by MemoryRepository", feature))
+	return c
+}
+
+// query load rows matching scope into dest, which could be a pointer of struct or slice
+func (r *MemoryRepository) query(scope *Scope, dest interface{}) *Scope {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rows, err := r.selectRows(scope, true)
+	if scope.Err(err) != nil {
+		return scope
+	}
+
+	results := indirect(reflect.ValueOf(dest))
+	switch results.Kind() {
+	case reflect.Slice:
+		var (
+			resultType = results.Type().Elem()
+			isPtr      = resultType.Kind() == reflect.Ptr
+			values     = reflect.MakeSlice(results.Type(), 0, len(rows))
+		)
+		if isPtr {
+			resultType = resultType.Elem()
+		}
+
+		for _, row := range rows {
+			elem := reflect.New(resultType)
+			if scope.Err(r.fromRow(scope.New(elem.Interface()), row)) != nil {
+				return scope
+			}
+
+			if isPtr {
+				values = reflect.Append(values, elem)
+			} else {
+				values = reflect.Append(values, elem.Elem())
+			}
+		}
+		results.Set(values)
+	case reflect.Struct:
+		if len(rows) == 0 {
+			scope.Err(ErrRecordNotFound)
+			return scope
+		}
+		scope.Err(r.fromRow(scope.New(results.Addr().Interface()), rows[0]))
+	default:
+		scope.Err(fmt.Errorf("unsupported destination, should be slice or struct"))
+		return scope
+	}
+
+	scope.db.SetRowsAffected(int64(len(rows)))
+	return scope
+}
+
+// update update rows matching scope with values, and set them to the scope's value
+func (r *MemoryRepository) update(scope *Scope, values interface{}, updateTime bool) *Scope {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	indexes, hasConditions, err := r.matchRows(scope)
+	if scope.Err(err) != nil {
+		return scope
+	}
+
+	if !hasConditions && r.blockGlobalUpdate {
+		scope.Err(errors.New("Missing WHERE clause while updating"))
+		return scope
+	}
+
+	updateAttrs, hasUpdate := scope.updatedAttrsWithValues(values)
+	if !hasUpdate {
+		return scope
+	}
+
+	for _, value := range updateAttrs {
+		if _, ok := value.(*Expression); ok {
+			scope.Err(fmt.Errorf("gorm: SQL expression %v is not supported by MemoryRepository", value))
+			return scope
+		}
+	}
+
+	if updateTime {
+		if field, ok := scope.FieldByName("UpdatedAt"); ok {
+			now := NowFunc()
+			if scope.IndirectValue().Kind() == reflect.Struct {
+				scope.Err(field.Set(now))
+			}
+			updateAttrs[field.DBName] = now
+		}
+	}
+
+	table := r.table(scope.TableName())
+	for _, idx := range indexes {
+		values := memoryRow{}
+		for column, value := range updateAttrs {
+			values[r.columnName(scope, column)] = copyMemoryValue(value)
+		}
+		r.updateRow(table.rows[idx], values)
+	}
+
+	scope.db.SetRowsAffected(int64(len(indexes)))
+	return scope
+}
+
+// insert insert scope's value into table, generates the primary key if it is blank and an integer
+func (r *MemoryRepository) insert(scope *Scope, table *memoryTable) error {
+	now := NowFunc()
+	for _, name := range []string{"CreatedAt", "UpdatedAt"} {
+		if field, ok := scope.FieldByName(name); ok && field.IsBlank {
+			if err := field.Set(now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if conflicted, err := r.resolveConflict(scope, table); conflicted || err != nil {
+		return err
+	}
+
+	for _, field := range scope.PrimaryFields() {
+		switch field.Field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if field.IsBlank {
+				table.lastID++
+				if err := field.Set(table.lastID); err != nil {
+					return err
+				}
+			} else if id, ok := normalizeMemoryValue(field.Field.Interface()).(int64); ok && id > table.lastID {
+				table.lastID = id
+			} else if id, ok := normalizeMemoryValue(field.Field.Interface()).(uint64); ok && int64(id) > table.lastID {
+				table.lastID = int64(id)
+			}
+		}
+	}
+
+	row := r.toRow(scope)
+	if len(scope.PrimaryFields()) > 0 {
+		for _, stored := range table.rows {
+			if r.samePrimaryKey(scope, stored, row) {
+				return fmt.Errorf("gorm: duplicated primary key %v for table %v", scope.PrimaryKeyValue(), scope.TableName())
+			}
+		}
+	}
+
+	table.rows = append(table.rows, row)
+	r.logUndo(func() {
+		if idx := table.indexOf(row); idx >= 0 {
+			table.rows = append(table.rows[:idx], table.rows[idx+1:]...)
+		}
+	})
+	return nil
+}
+
+// resolveConflict resolve conflicts set by `OnConflict`, returns true if scope's value conflicts with a stored record
+func (r *MemoryRepository) resolveConflict(scope *Scope, table *memoryTable) (bool, error) {
+	value, ok := scope.Get("gorm:on_conflict")
+	if !ok {
+		return false, nil
+	}
+
+	conflict := value.(Conflict)
+	conflictFields := scope.PrimaryFields()
+	if len(conflict.Columns) > 0 {
+		conflictFields = nil
+		for _, name := range conflict.Columns {
+			field, ok := scope.FieldByName(name)
+			if !ok {
+				return false, fmt.Errorf("gorm: unknown conflict column %v", name)
+			}
+			conflictFields = append(conflictFields, field)
+		}
+	}
+
+	row := r.toRow(scope)
+	for _, stored := range table.rows {
+		conflicted := len(conflictFields) > 0
+		for _, field := range conflictFields {
+			if result, ok := compareMemoryValues(stored[field.DBName], row[field.DBName]); field.IsBlank || !ok || result != 0 {
+				conflicted = false
+				break
+			}
+		}
+		if !conflicted {
+			continue
+		}
+
+		// like the SQL backends, a skipped value is left untouched
+		if conflict.DoNothing {
+			return true, nil
+		}
+
+		updates := conflict.DoUpdates
+		if len(updates) == 0 {
+			for column := range row {
+				if field, ok := scope.FieldByName(column); ok && !field.IsPrimaryKey && field.Name != "CreatedAt" {
+					updates = append(updates, column)
+				}
+			}
+		}
+
+		values := memoryRow{}
+		for _, name := range updates {
+			if field, ok := scope.FieldByName(name); ok {
+				values[field.DBName] = row[field.DBName]
+			}
+		}
+		r.updateRow(stored, values)
+		return true, r.fromRow(scope, stored)
+	}
+	return false, nil
+}
+
+func (r *MemoryRepository) samePrimaryKey(scope *Scope, a, b memoryRow) bool {
+	for _, field := range scope.PrimaryFields() {
+		if result, ok := compareMemoryValues(a[field.DBName], b[field.DBName]); !ok || result != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *MemoryRepository) toRow(scope *Scope) memoryRow {
+	row := memoryRow{}
+	for _, field := range scope.Fields() {
+		if field.IsNormal && !field.IsIgnored {
+			row[field.DBName] = copyMemoryValue(field.Field.Interface())
+		}
+	}
+	return row
+}
+
+func (r *MemoryRepository) fromRow(scope *Scope, row memoryRow) error {
+	for _, field := range scope.Fields() {
+		if value, ok := row[field.DBName]; ok && field.IsNormal && !field.IsIgnored {
+			if err := field.Set(copyMemoryValue(value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,825 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryRow map[string]interface{}
+
+type memoryTable struct {
+	rows   []memoryRow
+	lastID int64
+}
+
+// indexOf return the index of row in table, rows are compared by identity, -1 if not found
+func (table *memoryTable) indexOf(row memoryRow) int {
+	for idx, stored := range table.rows {
+		if reflect.ValueOf(stored).Pointer() == reflect.ValueOf(row).Pointer() {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (row memoryRow) clone() memoryRow {
+	clone := memoryRow{}
+	for column, value := range row {
+		clone[column] = copyMemoryValue(value)
+	}
+	return clone
+}
+
+// restore replace columns of row with previous ones
+func (row memoryRow) restore(previous memoryRow) {
+	for column := range row {
+		delete(row, column)
+	}
+	for column, value := range previous {
+		row[column] = value
+	}
+}
+
+// memoryStore is shared by a MemoryRepository and all relations created from it
+type memoryStore struct {
+	mu     sync.Mutex
+	tables map[string]*memoryTable
+}
+
+func (store *memoryStore) table(name string) *memoryTable {
+	table, ok := store.tables[name]
+	if !ok {
+		table = &memoryTable{}
+		store.tables[name] = table
+	}
+	return table
+}
+
+// memoryTransaction keeps an undo log of writes made in the transaction, which are reverted in reverse order on `Rollback` and
+// `RollbackTo`, so writes made outside the transaction are kept; generated primary keys are not reverted, like sequences of databases
+type memoryTransaction struct {
+	undo       []func()
+	savePoints map[string]int
+	done       bool
+}
+
+// rollbackTo revert writes logged after the first n ones
+func (tx *memoryTransaction) rollbackTo(n int) {
+	for idx := len(tx.undo) - 1; idx >= n; idx-- {
+		tx.undo[idx]()
+	}
+	tx.undo = tx.undo[:n]
+}
+
+// logUndo append fc reverting a write to the undo log of current transaction, the store must be locked
+func (r *MemoryRepository) logUndo(fc func()) {
+	if r.tx != nil && !r.tx.done {
+		r.tx.undo = append(r.tx.undo, fc)
+	}
+}
+
+// table return the table with name, it is created and logged if not exist
+func (r *MemoryRepository) table(name string) *memoryTable {
+	if table, ok := r.store.tables[name]; ok {
+		return table
+	}
+
+	table := r.store.table(name)
+	r.logUndo(func() {
+		if r.store.tables[name] == table {
+			delete(r.store.tables, name)
+		}
+	})
+	return table
+}
+
+// updateRow update columns of a stored row in place, previous values are logged
+func (r *MemoryRepository) updateRow(row memoryRow, values memoryRow) {
+	previous := row.clone()
+	r.logUndo(func() { row.restore(previous) })
+	for column, value := range values {
+		row[column] = value
+	}
+}
+
+type memoryCondition func(row memoryRow) bool
+
+var (
+	memoryAndRegexp        = regexp.MustCompile(`(?i)\s+AND\s+`)
+	memoryExpressionRegexp = regexp.MustCompile("(?i)^([\\w.\"`]+)\\s*(=|<>|!=|>=|<=|>|<|NOT\\s+IN|IN|NOT\\s+LIKE|LIKE|IS\\s+NOT|IS)\\s*(.+)$")
+	memoryOrderRegexp      = regexp.MustCompile("(?i)^([\\w.\"`]+)(\\s+(ASC|DESC))?$")
+)
+
+// whereCondition build the condition of scope's search, similar to `Scope.whereSQL`
+func (r *MemoryRepository) whereCondition(scope *Scope) (memoryCondition, bool, error) {
+	var (
+		deletedAtField, hasDeletedAtField = scope.FieldByName("DeletedAt")
+		primaryConditions                 []memoryCondition
+	)
+
+	if !scope.Search.Unscoped && hasDeletedAtField {
+		primaryConditions = append(primaryConditions, memoryIsNull(deletedAtField.DBName))
+	}
+
+	if scope.IndirectValue().Kind() == reflect.Struct && !scope.PrimaryKeyZero() {
+		for _, field := range scope.PrimaryFields() {
+			primaryConditions = append(primaryConditions, memoryEqual(field.DBName, field.Field.Interface()))
+		}
+	}
+
+	searchCondition, hasSearchConditions, err := r.searchCondition(scope, scope.Search)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return func(row memoryRow) bool {
+		for _, condition := range primaryConditions {
+			if !condition(row) {
+				return false
+			}
+		}
+		return searchCondition(row)
+	}, hasSearchConditions || len(primaryConditions) > 0, nil
+}
+
+// searchCondition build the condition of where, or and not conditions of a search, refer `Scope.searchConditionsSQL`
+func (r *MemoryRepository) searchCondition(scope *Scope, search *Search) (memoryCondition, bool, error) {
+	if search == nil {
+		return memoryAlways, false, nil
+	}
+
+	var andConditions, orConditions []memoryCondition
+	for _, clause := range search.whereConditions {
+		condition, err := r.buildCondition(scope, clause, true)
+		if err != nil {
+			return nil, false, err
+		}
+		andConditions = append(andConditions, condition)
+	}
+
+	for _, clause := range search.orConditions {
+		condition, err := r.buildCondition(scope, clause, true)
+		if err != nil {
+			return nil, false, err
+		}
+		orConditions = append(orConditions, condition)
+	}
+
+	for _, clause := range search.notConditions {
+		condition, err := r.buildCondition(scope, clause, false)
+		if err != nil {
+			return nil, false, err
+		}
+		andConditions = append(andConditions, condition)
+	}
+
+	hasConditions := len(andConditions) > 0 || len(orConditions) > 0
+	return func(row memoryRow) bool {
+		if len(andConditions) > 0 {
+			if memoryAnd(andConditions)(row) {
+				return true
+			}
+		} else if len(orConditions) == 0 {
+			return true
+		}
+		return memoryOr(orConditions)(row)
+	}, hasConditions, nil
+}
+
+// buildGroupCondition build condition of a condition group, refer `Scope.buildGroupCondition`
+func (r *MemoryRepository) buildGroupCondition(scope *Scope, group *Condition, include bool) (memoryCondition, error) {
+	var conditions []memoryCondition
+	for _, query := range group.conditions {
+		condition, err := r.buildCondition(scope, map[string]interface{}{"query": query, "args": []interface{}{}}, true)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	condition := memoryAnd(conditions)
+	if group.operator == "OR" && len(conditions) > 0 {
+		condition = memoryOr(conditions)
+	}
+
+	if (group.operator == "NOT") == include && len(conditions) > 0 {
+		return memoryNot(condition), nil
+	}
+	return condition, nil
+}
+
+// buildColumnCondition build condition of a column condition, refer `Scope.buildColumnCondition`, sub queries are not supported
+func (r *MemoryRepository) buildColumnCondition(scope *Scope, condition *ColumnCondition) (memoryCondition, error) {
+	_, column, err := scope.columnConditionDBName(condition.column)
+	if err != nil {
+		return nil, err
+	}
+
+	switch condition.operator {
+	case "=":
+		return memoryEqual(column, condition.values[0]), nil
+	case "<>":
+		return memoryNot(memoryEqual(column, condition.values[0])), nil
+	case ">", ">=", "<", "<=":
+		return memoryCompare(column, condition.operator, condition.values[0]), nil
+	case "BETWEEN":
+		return memoryAnd([]memoryCondition{memoryCompare(column, ">=", condition.values[0]), memoryCompare(column, "<=", condition.values[1])}), nil
+	case "LIKE":
+		return memoryLike(column, fmt.Sprint(condition.values[0])), nil
+	case "NOT LIKE":
+		return memoryNot(memoryLike(column, fmt.Sprint(condition.values[0]))), nil
+	case "IN", "NOT IN":
+		if _, ok := condition.values[0].(*Expression); ok {
+			return nil, fmt.Errorf("gorm: sub query of column %v is not supported by MemoryRepository", condition.column)
+		}
+		if condition.operator == "NOT IN" {
+			return memoryNot(memoryIn(column, condition.values[0])), nil
+		}
+		return memoryIn(column, condition.values[0]), nil
+	case "IS NULL":
+		return memoryIsNull(column), nil
+	default:
+		return memoryNot(memoryIsNull(column)), nil
+	}
+}
+
+// buildSearchCondition build condition of a search used as a condition, refer `Scope.buildSearchCondition`
+func (r *MemoryRepository) buildSearchCondition(scope *Scope, search *Search, include bool) (memoryCondition, error) {
+	condition, hasConditions, err := r.searchCondition(scope, search)
+	if err != nil {
+		return nil, err
+	}
+
+	if !include && hasConditions {
+		return memoryNot(condition), nil
+	}
+	return condition, nil
+}
+
+// buildCondition build condition for a where/not clause, supports the same query types as `Scope.buildCondition`,
+// string queries are limited to comparisons like `name = ?`, `age > 18`, `id IN (?)`, `name LIKE ?`, `deleted_at IS NULL` joined with `AND`
+func (r *MemoryRepository) buildCondition(scope *Scope, clause map[string]interface{}, include bool) (memoryCondition, error) {
+	var (
+		primaryKey = scope.PrimaryKey()
+		args, _    = clause["args"].([]interface{})
+		conditions []memoryCondition
+	)
+
+	switch value := clause["query"].(type) {
+	case sql.NullInt64:
+		conditions = append(conditions, memoryEqual(primaryKey, value.Int64))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		conditions = append(conditions, memoryEqual(primaryKey, value))
+	case []int, []int8, []int16, []int32, []int64, []uint, []uint8, []uint16, []uint32, []uint64, []string, []interface{}:
+		if !include && reflect.ValueOf(value).Len() == 0 {
+			return memoryAlways, nil
+		}
+		conditions = append(conditions, memoryIn(primaryKey, value))
+	case string:
+		if isNumberRegexp.MatchString(value) {
+			conditions = append(conditions, memoryEqual(primaryKey, strings.TrimSpace(value)))
+		} else if value == "" {
+			return memoryAlways, nil
+		} else if columnRegexp.MatchString(value) && len(args) == 1 {
+			conditions = append(conditions, memoryIn(r.columnName(scope, value), args[0]))
+		} else {
+			condition, err := r.parseExpression(scope, value, args)
+			if err != nil {
+				return nil, err
+			}
+			if !include {
+				return memoryNot(condition), nil
+			}
+			return condition, nil
+		}
+	case map[string]interface{}:
+		for key, value := range value {
+			if value == nil {
+				conditions = append(conditions, memoryIsNull(r.columnName(scope, key)))
+			} else {
+				conditions = append(conditions, memoryIn(r.columnName(scope, key), value))
+			}
+		}
+	case *Condition:
+		return r.buildGroupCondition(scope, value, include)
+	case *ColumnCondition:
+		condition, err := r.buildColumnCondition(scope, value)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			return memoryNot(condition), nil
+		}
+		return condition, nil
+	case *Search:
+		return r.buildSearchCondition(scope, value, include)
+	case func(Repository) Repository:
+		return r.buildSearchCondition(scope, value(scope.NewDB()).Search(), include)
+	case *Expression:
+		if value.expr == "" {
+			return memoryAlways, nil
+		}
+
+		condition, err := r.parseExpression(scope, value.expr, value.args)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			return memoryNot(condition), nil
+		}
+		return condition, nil
+	case interface{}:
+		newScope := scope.New(value)
+		if len(newScope.Fields()) == 0 {
+			return nil, fmt.Errorf("invalid query condition: %v", value)
+		}
+
+		for _, field := range newScope.Fields() {
+			if !field.IsIgnored && !field.IsBlank {
+				conditions = append(conditions, memoryEqual(field.DBName, field.Field.Interface()))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid query condition: %v", value)
+	}
+
+	if !include {
+		for idx, condition := range conditions {
+			conditions[idx] = memoryNot(condition)
+		}
+	}
+	return memoryAnd(conditions), nil
+}
+
+func (r *MemoryRepository) parseExpression(scope *Scope, expression string, args []interface{}) (memoryCondition, error) {
+	var conditions []memoryCondition
+
+	for _, part := range memoryAndRegexp.Split(trimMemoryParentheses(expression), -1) {
+		matches := memoryExpressionRegexp.FindStringSubmatch(trimMemoryParentheses(part))
+		if matches == nil {
+			return nil, fmt.Errorf("gorm: condition %v is not supported by MemoryRepository", expression)
+		}
+
+		var (
+			column   = r.columnName(scope, matches[1])
+			operator = strings.ToUpper(strings.Join(strings.Fields(matches[2]), " "))
+			operand  = strings.TrimSpace(matches[3])
+			value    interface{}
+		)
+
+		if operand == "?" || operand == "(?)" {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("gorm: missing argument for condition %v", expression)
+			}
+			value, args = args[0], args[1:]
+		} else if literal, ok := parseMemoryLiteral(operand); ok {
+			value = literal
+		} else {
+			return nil, fmt.Errorf("gorm: condition %v is not supported by MemoryRepository", expression)
+		}
+
+		switch operator {
+		case "=":
+			conditions = append(conditions, memoryEqual(column, value))
+		case "<>", "!=":
+			conditions = append(conditions, memoryNot(memoryEqual(column, value)))
+		case ">", ">=", "<", "<=":
+			conditions = append(conditions, memoryCompare(column, operator, value))
+		case "IN":
+			conditions = append(conditions, memoryIn(column, value))
+		case "NOT IN":
+			conditions = append(conditions, memoryNot(memoryIn(column, value)))
+		case "LIKE":
+			conditions = append(conditions, memoryLike(column, fmt.Sprint(value)))
+		case "NOT LIKE":
+			conditions = append(conditions, memoryNot(memoryLike(column, fmt.Sprint(value))))
+		case "IS":
+			conditions = append(conditions, memoryIsNull(column))
+		case "IS NOT":
+			conditions = append(conditions, memoryNot(memoryIsNull(column)))
+		}
+	}
+
+	if len(args) > 0 {
+		return nil, fmt.Errorf("gorm: too many arguments for condition %v", expression)
+	}
+	return memoryAnd(conditions), nil
+}
+
+// columnName convert `users.name`, `"name"` or a field name to the column name
+func (r *MemoryRepository) columnName(scope *Scope, name string) string {
+	name = strings.Trim(name, "\"`")
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = strings.Trim(name[idx+1:], "\"`")
+	}
+
+	if scope.Value != nil {
+		if field, ok := scope.FieldByName(name); ok {
+			return field.DBName
+		}
+	}
+	return name
+}
+
+// selectRows return rows of scope's table that match scope's search, ordered and paginated
+func (r *MemoryRepository) selectRows(scope *Scope, paginate bool) ([]memoryRow, error) {
+	indexes, _, err := r.matchRows(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	table := r.store.tables[scope.TableName()]
+	rows := make([]memoryRow, len(indexes))
+	for idx, rowIdx := range indexes {
+		rows[idx] = table.rows[rowIdx]
+	}
+
+	var orders []interface{}
+	orders = append(orders, scope.Search.orders...)
+	if orderBy, ok := scope.Get("gorm:order_by_primary_key"); ok {
+		if primaryKey := scope.PrimaryKey(); primaryKey != "" {
+			orders = append(orders, fmt.Sprintf("%v %v", primaryKey, orderBy))
+		}
+	}
+
+	if err := r.sortRows(scope, rows, orders); err != nil {
+		return nil, err
+	}
+
+	if paginate {
+		if offset, ok := memoryNumber(scope.Search.offset); ok {
+			if offset > len(rows) {
+				offset = len(rows)
+			}
+			rows = rows[offset:]
+		}
+
+		if limit, ok := memoryNumber(scope.Search.limit); ok && limit < len(rows) {
+			rows = rows[:limit]
+		}
+	}
+	return rows, nil
+}
+
+// matchRows return indexes of rows in scope's table that match scope's search
+func (r *MemoryRepository) matchRows(scope *Scope) (indexes []int, hasConditions bool, err error) {
+	condition, hasConditions, err := r.whereCondition(scope)
+	if err != nil {
+		return nil, hasConditions, err
+	}
+
+	if table, ok := r.store.tables[scope.TableName()]; ok {
+		for idx, row := range table.rows {
+			if condition(row) {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	return indexes, hasConditions, nil
+}
+
+func (r *MemoryRepository) sortRows(scope *Scope, rows []memoryRow, orders []interface{}) error {
+	type memoryOrder struct {
+		column string
+		desc   bool
+	}
+
+	var sorts []memoryOrder
+	for _, order := range orders {
+		str, ok := order.(string)
+		if !ok {
+			return fmt.Errorf("gorm: order %v is not supported by MemoryRepository", order)
+		}
+
+		for _, part := range strings.Split(str, ",") {
+			matches := memoryOrderRegexp.FindStringSubmatch(strings.TrimSpace(part))
+			if matches == nil {
+				return fmt.Errorf("gorm: order %v is not supported by MemoryRepository", order)
+			}
+			sorts = append(sorts, memoryOrder{column: r.columnName(scope, matches[1]), desc: strings.EqualFold(matches[3], "DESC")})
+		}
+	}
+
+	if len(sorts) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, order := range sorts {
+				result, _ := compareMemoryValues(rows[i][order.column], rows[j][order.column])
+				if result != 0 {
+					return (result < 0) != order.desc
+				}
+			}
+			return false
+		})
+	}
+	return nil
+}
+
+func memoryAlways(row memoryRow) bool {
+	return true
+}
+
+func memoryAnd(conditions []memoryCondition) memoryCondition {
+	return func(row memoryRow) bool {
+		for _, condition := range conditions {
+			if !condition(row) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func memoryOr(conditions []memoryCondition) memoryCondition {
+	return func(row memoryRow) bool {
+		for _, condition := range conditions {
+			if condition(row) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func memoryNot(condition memoryCondition) memoryCondition {
+	return func(row memoryRow) bool {
+		return !condition(row)
+	}
+}
+
+func memoryIsNull(column string) memoryCondition {
+	return func(row memoryRow) bool {
+		return normalizeMemoryValue(row[column]) == nil
+	}
+}
+
+func memoryEqual(column string, value interface{}) memoryCondition {
+	return func(row memoryRow) bool {
+		result, ok := compareMemoryValues(row[column], value)
+		return ok && result == 0
+	}
+}
+
+func memoryIn(column string, value interface{}) memoryCondition {
+	if _, ok := value.([]byte); ok {
+		return memoryEqual(column, value)
+	}
+
+	if _, ok := value.(driver.Valuer); !ok {
+		if values := reflect.ValueOf(value); values.Kind() == reflect.Slice || values.Kind() == reflect.Array {
+			var conditions []memoryCondition
+			for i := 0; i < values.Len(); i++ {
+				conditions = append(conditions, memoryEqual(column, values.Index(i).Interface()))
+			}
+
+			return func(row memoryRow) bool {
+				for _, condition := range conditions {
+					if condition(row) {
+						return true
+					}
+				}
+				return false
+			}
+		}
+	}
+	return memoryEqual(column, value)
+}
+
+func memoryCompare(column string, operator string, value interface{}) memoryCondition {
+	return func(row memoryRow) bool {
+		result, ok := compareMemoryValues(row[column], value)
+		if !ok {
+			return false
+		}
+
+		switch operator {
+		case ">":
+			return result > 0
+		case ">=":
+			return result >= 0
+		case "<":
+			return result < 0
+		default:
+			return result <= 0
+		}
+	}
+}
+
+func memoryLike(column string, pattern string) memoryCondition {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, char := range pattern {
+		switch char {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	expr.WriteString("$")
+	likeRegexp := regexp.MustCompile(expr.String())
+
+	return func(row memoryRow) bool {
+		value, ok := normalizeMemoryValue(row[column]).(string)
+		return ok && likeRegexp.MatchString(value)
+	}
+}
+
+func trimMemoryParentheses(str string) string {
+	str = strings.TrimSpace(str)
+	for strings.HasPrefix(str, "(") && strings.HasSuffix(str, ")") && !strings.HasPrefix(str, "(?") {
+		str = strings.TrimSpace(str[1 : len(str)-1])
+	}
+	return str
+}
+
+func parseMemoryLiteral(str string) (interface{}, bool) {
+	if strings.EqualFold(str, "NULL") {
+		return nil, true
+	}
+
+	if strings.EqualFold(str, "TRUE") || strings.EqualFold(str, "FALSE") {
+		return strings.EqualFold(str, "TRUE"), true
+	}
+
+	if len(str) >= 2 && str[0] == '\'' && str[len(str)-1] == '\'' {
+		return strings.Replace(str[1:len(str)-1], "''", "'", -1), true
+	}
+
+	if value, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return value, true
+	}
+
+	if value, err := strconv.ParseFloat(str, 64); err == nil {
+		return value, true
+	}
+	return nil, false
+}
+
+func memoryNumber(value interface{}) (int, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(fmt.Sprint(value))
+	return number, err == nil && number >= 0
+}
+
+// normalizeMemoryValue convert value to one of nil, int64, uint64, float64, string, bool, time.Time if possible
+func normalizeMemoryValue(value interface{}) interface{} {
+	for {
+		if valuer, ok := value.(driver.Valuer); ok {
+			if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() {
+				return nil
+			}
+
+			v, err := valuer.Value()
+			if err != nil {
+				return value
+			}
+			value = v
+		}
+
+		reflectValue := reflect.ValueOf(value)
+		if !reflectValue.IsValid() {
+			return nil
+		}
+
+		switch reflectValue.Kind() {
+		case reflect.Ptr:
+			if reflectValue.IsNil() {
+				return nil
+			}
+			value = reflectValue.Elem().Interface()
+			continue
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflectValue.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return reflectValue.Uint()
+		case reflect.Float32, reflect.Float64:
+			return reflectValue.Float()
+		case reflect.String:
+			return reflectValue.String()
+		case reflect.Bool:
+			return reflectValue.Bool()
+		case reflect.Slice:
+			if reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+				return string(reflectValue.Bytes())
+			}
+		}
+		return value
+	}
+}
+
+// compareMemoryValues compare two values, returns false if they are not comparable, NULL is less than any other value
+func compareMemoryValues(a, b interface{}) (int, bool) {
+	a, b = normalizeMemoryValue(a), normalizeMemoryValue(b)
+	a, b = coerceMemoryNumber(a, b), coerceMemoryNumber(b, a)
+
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0, false
+		case a == nil:
+			return -1, false
+		default:
+			return 1, false
+		}
+	}
+
+	if x, ok := memoryFloat(a); ok {
+		if y, ok := memoryFloat(b); ok {
+			if xi, ok := a.(int64); ok {
+				if yi, ok := b.(int64); ok {
+					return compareMemoryOrdered(xi < yi, xi > yi), true
+				}
+			}
+			return compareMemoryOrdered(x < y, x > y), true
+		}
+		return 0, false
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareMemoryOrdered(!x && y, x && !y), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return compareMemoryOrdered(x.Before(y), x.After(y)), true
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return 0, true
+	}
+	return 0, false
+}
+
+// coerceMemoryNumber convert a numeric string to number when it is compared with a number
+func coerceMemoryNumber(value, other interface{}) interface{} {
+	if str, ok := value.(string); ok {
+		if _, ok := memoryFloat(other); ok {
+			if number, ok := parseMemoryLiteral(strings.TrimSpace(str)); ok {
+				if _, ok := memoryFloat(number); ok {
+					return number
+				}
+			}
+		}
+	}
+	return value
+}
+
+func compareMemoryOrdered(less, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
+
+func memoryFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// copyMemoryValue copy pointers and byte slices, so stored rows won't be changed through the saved struct
+func copyMemoryValue(value interface{}) interface{} {
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Ptr:
+		if !reflectValue.IsNil() {
+			clone := reflect.New(reflectValue.Type().Elem())
+			clone.Elem().Set(reflectValue.Elem())
+			return clone.Interface()
+		}
+	case reflect.Slice:
+		if !reflectValue.IsNil() && reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			clone := reflect.MakeSlice(reflectValue.Type(), reflectValue.Len(), reflectValue.Len())
+			reflect.Copy(clone, reflectValue)
+			return clone.Interface()
+		}
+	}
+	return value
+}
@@ -0,0 +1,271 @@
+package gorm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MemoryAccount struct {
+	ID        uint
+	Name      string
+	Age       int
+	Email     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func TestMemoryRepositoryCreateAndQuery(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	email := "jinzhu@example.org"
+	accounts := []MemoryAccount{{Name: "jinzhu", Age: 18, Email: &email}, {Name: "jinzhu2", Age: 20}, {Name: "jinzhu3", Age: 22}}
+	for idx := range accounts {
+		if err := db.Create(&accounts[idx]).Error(); err != nil {
+			t.Fatalf("No error should happen when creating, but got %v", err)
+		}
+	}
+
+	if accounts[0].ID != 1 || accounts[2].ID != 3 || accounts[0].CreatedAt.IsZero() {
+		t.Errorf("Should generate primary key and timestamps, but got %v, %v", accounts[0].ID, accounts[2].ID)
+	}
+
+	if err := db.Create(&MemoryAccount{ID: 1}).Error(); err == nil {
+		t.Errorf("Should return error for duplicated primary key")
+	}
+
+	var account MemoryAccount
+	if err := db.First(&account, "name = ?", "jinzhu2").Error(); err != nil || account.ID != 2 {
+		t.Errorf("Should find record with string condition, but got %v, %v", account, err)
+	}
+
+	account = MemoryAccount{}
+	if db.First(&account, 1); account.Email == nil || *account.Email != email {
+		t.Errorf("Should find record with primary key, but got %v", account)
+	}
+
+	*account.Email = "changed@example.org"
+	var reloaded MemoryAccount
+	if db.First(&reloaded, 1); *reloaded.Email != email {
+		t.Errorf("Changing a loaded record shouldn't change stored record")
+	}
+
+	var last MemoryAccount
+	if db.Where(&MemoryAccount{Age: 18}).Or(map[string]interface{}{"name": "jinzhu2"}).Last(&last); last.ID != 2 {
+		t.Errorf("Should find last record with struct and map conditions, but got %v", last)
+	}
+
+	var found []MemoryAccount
+	db.Where("age > ? AND name <> ?", 18, "jinzhu3").Or("id IN (?)", []uint{3}).Order("age desc").Find(&found)
+	if len(found) != 2 || found[0].Name != "jinzhu3" || found[1].Name != "jinzhu2" {
+		t.Errorf("Should find records with conditions and order, but got %v", found)
+	}
+
+	var paged []*MemoryAccount
+	db.Not("name", []string{"jinzhu"}).Order("id").Limit(1).Offset(1).Find(&paged)
+	if len(paged) != 1 || paged[0].Name != "jinzhu3" {
+		t.Errorf("Should find records with limit and offset, but got %v", paged)
+	}
+
+	var count int
+	if db.Model(&MemoryAccount{}).Where("age >= ?", 20).Count(&count); count != 2 {
+		t.Errorf("Should count matched records, but got %v", count)
+	}
+
+	var names []string
+	if db.Model(&MemoryAccount{}).Order("age desc").Pluck("name", &names); len(names) != 3 || names[0] != "jinzhu3" {
+		t.Errorf("Should pluck column of matched records, but got %v", names)
+	}
+
+	if err := db.First(&MemoryAccount{}, "name = ?", "unknown").Error(); err != gorm.ErrRecordNotFound {
+		t.Errorf("Should return record not found error, but got %v", err)
+	}
+
+	if err := db.Joins("JOIN emails ON emails.account_id = accounts.id").Find(&found).Error(); err == nil {
+		t.Errorf("Should return error for unsupported joins")
+	}
+}
+
+func TestMemoryRepositoryGroupedConditions(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	for _, account := range []MemoryAccount{{Name: "jinzhu", Age: 18}, {Name: "jinzhu2", Age: 20}, {Name: "jinzhu3", Age: 22}} {
+		db.Create(&account)
+	}
+
+	var accounts []MemoryAccount
+	if db.Where(gorm.Or("age = 18", gorm.Expr("name = ?", "jinzhu3"))).Where("age > ?", 18).Find(&accounts); len(accounts) != 1 || accounts[0].Name != "jinzhu3" {
+		t.Errorf("Should find records with grouped conditions, but got %v", accounts)
+	}
+
+	if db.Not(func(db gorm.Repository) gorm.Repository {
+		return db.Where("age = ?", 18).Or("age = ?", 22)
+	}).Find(&accounts); len(accounts) != 1 || accounts[0].Name != "jinzhu2" {
+		t.Errorf("Should find records not matching grouped conditions, but got %v", accounts)
+	}
+}
+
+func TestMemoryRepositoryColumnConditions(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	for _, account := range []MemoryAccount{{Name: "jinzhu", Age: 18}, {Name: "jinzhu2", Age: 20}, {Name: "jinzhu3", Age: 22}} {
+		db.Create(&account)
+	}
+
+	var accounts []MemoryAccount
+	if db.Where(gorm.Col("age").Between(19, 22)).Where(gorm.Col("Name").NotIn([]string{"jinzhu3"})).Find(&accounts); len(accounts) != 1 || accounts[0].Name != "jinzhu2" {
+		t.Errorf("Should find records with column conditions, but got %v", accounts)
+	}
+
+	if err := db.Where(gorm.Col("unknown").IsNull()).Find(&accounts).Error(); err == nil {
+		t.Errorf("Should return error for unknown column")
+	}
+}
+
+func TestMemoryRepositoryUpdateAndDelete(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	account := MemoryAccount{Name: "update", Age: 10}
+	db.Create(&account)
+	db.Create(&MemoryAccount{Name: "other", Age: 10})
+
+	if rows := db.Model(&account).Update("age", 11).RowsAffected(); rows != 1 || account.Age != 11 {
+		t.Errorf("Should update record with primary key, but got %v, %v", rows, account.Age)
+	}
+
+	if rows := db.Model(&MemoryAccount{}).Where("age = ?", 10).Updates(map[string]interface{}{"name": "updated"}).RowsAffected(); rows != 1 {
+		t.Errorf("Should update matched records, but got %v", rows)
+	}
+
+	account.Name = "saved"
+	db.Save(&account)
+
+	var names []string
+	db.Model(&MemoryAccount{}).Order("id").Pluck("name", &names)
+	if len(names) != 2 || names[0] != "saved" || names[1] != "updated" {
+		t.Errorf("Should save and update records, but got %v", names)
+	}
+
+	db.Delete(&account)
+	var count int
+	if db.Model(&MemoryAccount{}).Count(&count); count != 1 {
+		t.Errorf("Should not find soft deleted record, but got %v", count)
+	}
+
+	if db.Unscoped().Model(&MemoryAccount{}).Count(&count); count != 2 {
+		t.Errorf("Should find soft deleted record with Unscoped, but got %v", count)
+	}
+
+	db.Unscoped().Delete(&MemoryAccount{}, "name = ?", "updated")
+	if db.Unscoped().Model(&MemoryAccount{}).Count(&count); count != 1 {
+		t.Errorf("Should delete record permanently with Unscoped, but got %v", count)
+	}
+}
+
+func TestMemoryRepositoryTransaction(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	err := db.Transaction(func(tx gorm.Repository) error {
+		tx.Create(&MemoryAccount{Name: "committed"})
+		tx.Transaction(func(tx gorm.Repository) error {
+			tx.Create(&MemoryAccount{Name: "rolled back"})
+			return errors.New("rollback")
+		})
+		return nil
+	})
+	if err != nil {
+		t.Errorf("No error should happen, but got %v", err)
+	}
+
+	db.Transaction(func(tx gorm.Repository) error {
+		tx.Create(&MemoryAccount{Name: "rolled back"})
+		return errors.New("rollback")
+	})
+
+	var names []string
+	if db.Model(&MemoryAccount{}).Pluck("name", &names); len(names) != 1 || names[0] != "committed" {
+		t.Errorf("Should only keep committed records, but got %v", names)
+	}
+}
+
+func TestMemoryRepositoryRollbackKeepsOtherWrites(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	db.Create(&MemoryAccount{Name: "existing", Age: 18})
+
+	tx := db.Begin()
+	tx.Create(&MemoryAccount{Name: "in transaction"})
+	tx.Model(&MemoryAccount{}).Where("name = ?", "existing").Update("age", 20)
+	tx.SavePoint("sp")
+	tx.Where("name = ?", "existing").Delete(&MemoryAccount{})
+	tx.Create(&MemoryAccount{Name: "after savepoint"})
+	db.Create(&MemoryAccount{Name: "outside"})
+
+	tx.RollbackTo("sp")
+	var names []string
+	if db.Model(&MemoryAccount{}).Order("id").Pluck("name", &names); len(names) != 3 || names[0] != "existing" || names[1] != "in transaction" || names[2] != "outside" {
+		t.Errorf("Should only revert writes after the savepoint, but got %v", names)
+	}
+
+	tx.Rollback()
+	names = nil
+	if db.Model(&MemoryAccount{}).Order("id").Pluck("name", &names); len(names) != 2 || names[0] != "existing" || names[1] != "outside" {
+		t.Errorf("Should keep writes made outside of the transaction, but got %v", names)
+	}
+
+	var account MemoryAccount
+	if db.First(&account, "name = ?", "existing"); account.Age != 18 {
+		t.Errorf("Should revert updates made in the transaction, but got %v", account.Age)
+	}
+}
+
+func TestMemoryRepositoryRollbackRenames(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	db.Create(&MemoryAccount{Name: "renamed"})
+
+	tx := db.Begin()
+	tx.Model(&MemoryAccount{}).RenameColumn("name", "title")
+	tx.RenameTable("memory_accounts", "accounts")
+	if !tx.HasTable("accounts") || tx.HasTable("memory_accounts") {
+		t.Fatalf("Should rename table in transaction")
+	}
+	tx.Rollback()
+
+	var account MemoryAccount
+	if err := db.First(&account, "name = ?", "renamed").Error(); err != nil || db.HasTable("accounts") {
+		t.Errorf("Should revert renames made in the transaction, but got %v", err)
+	}
+}
+
+func TestMemoryRepositoryAssociation(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	association := db.Model(&MemoryAccount{ID: 1}).Association("Emails")
+	if count := association.Count(); count != 0 || association.Error() == nil {
+		t.Errorf("Should return an association with error, but got %v, %v", count, association.Error())
+	}
+}
+
+func TestMemoryRepositoryOnConflict(t *testing.T) {
+	db := gorm.NewMemoryRepository()
+	db.Create(&MemoryAccount{Name: "jinzhu", Age: 18})
+
+	account := MemoryAccount{Name: "jinzhu", Age: 20}
+	if err := db.OnConflict(gorm.Conflict{Columns: []string{"name"}, DoUpdates: []string{"age"}}).Create(&account).Error(); err != nil {
+		t.Fatalf("No error should happen when upsert, but got %v", err)
+	}
+
+	var count int
+	db.Model(&MemoryAccount{}).Count(&count)
+	if count != 1 || account.ID != 1 || account.Age != 20 {
+		t.Errorf("Should update the conflicted record, but got %v records, %v", count, account)
+	}
+
+	skipped := MemoryAccount{Name: "jinzhu", Age: 30}
+	db.OnConflict(gorm.Conflict{Columns: []string{"name"}, DoNothing: true}).Create(&skipped)
+	if skipped.ID != 0 || skipped.Age != 30 {
+		t.Errorf("Should leave the skipped value untouched, but got %v", skipped)
+	}
+
+	var stored MemoryAccount
+	db.First(&stored, 1)
+	if stored.Age != 20 {
+		t.Errorf("Should keep the conflicted record unchanged, but got %v", stored)
+	}
+}
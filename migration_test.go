@@ -1,6 +1,7 @@
 package gorm_test
 
 import (
+	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -530,3 +532,496 @@ func TestModifyColumnType(t *testing.T) {
 		t.Errorf("No error should happen when ModifyColumn, but got %v", err)
 	}
 }
+
+type ColumnDrift struct {
+	ID   uint
+	Name string `gorm:"size:64"`
+	Age  int
+}
+
+type ColumnDriftResized struct {
+	ID   uint
+	Name string `gorm:"size:128;not null"`
+	Age  int
+}
+
+func (ColumnDriftResized) TableName() string {
+	return "column_drifts"
+}
+
+type ColumnDriftShrunk struct {
+	ID   uint
+	Name string `gorm:"size:32"`
+	Age  int
+}
+
+func (ColumnDriftShrunk) TableName() string {
+	return "column_drifts"
+}
+
+func TestAutoMigrateModifyColumns(t *testing.T) {
+	DB.DropTableIfExists(&ColumnDrift{})
+	DB.AutoMigrate(&ColumnDrift{})
+
+	columnSize := func(name string) (size int, nullable bool) {
+		columnTypes, err := DB.Dialect().ColumnTypes("column_drifts")
+		if err != nil {
+			t.Fatalf("No error should happen when get column types, but got %v", err)
+		}
+
+		for _, columnType := range columnTypes {
+			if columnType.Name == name {
+				return columnType.Size, columnType.Nullable
+			}
+		}
+		t.Fatalf("Column %v not found in %+v", name, columnTypes)
+		return
+	}
+
+	if size, nullable := columnSize("name"); size != 64 || !nullable {
+		t.Errorf("Column name should be a nullable column of size 64, but got %v, %v", size, nullable)
+	}
+
+	if err := DB.AutoMigrate(&ColumnDriftResized{}).Error(); err != nil {
+		t.Errorf("No error should happen when auto migrate, but got %v", err)
+	}
+
+	if size, _ := columnSize("name"); size != 64 {
+		t.Errorf("Columns should not be modified by default, but got size %v", size)
+	}
+
+	var buf bytes.Buffer
+	logged := DB.New().SetContextLogger(gorm.NewJSONLogger(&buf, gorm.DefaultLoggerConfig))
+	if err := logged.Set("gorm:auto_migrate_modify_columns", true).AutoMigrate(&ColumnDriftShrunk{}).Error(); err != nil {
+		t.Errorf("No error should happen when skipping destructive changes, but got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"level":"warn"`) || !strings.Contains(buf.String(), "skipped destructive change of column") {
+		t.Errorf("Skipped destructive changes should be logged as warnings, but got %v", buf.String())
+	}
+
+	if size, _ := columnSize("name"); size != 64 {
+		t.Errorf("Destructive changes should be skipped without flag, but got size %v", size)
+	}
+
+	if err := DB.Set("gorm:auto_migrate_modify_columns", true).Set("gorm:auto_migrate_destructive", true).AutoMigrate(&ColumnDriftResized{}).Error(); err != nil {
+		t.Errorf("No error should happen when modify columns, but got %v", err)
+	}
+
+	if size, nullable := columnSize("name"); size != 128 || nullable {
+		t.Errorf("Column name should be modified to not null column of size 128, but got %v, %v", size, nullable)
+	}
+
+	if err := DB.WithAutoMigrateOptions(gorm.AutoMigrateOptions{ModifyColumns: true, Destructive: true}).AutoMigrate(&ColumnDriftShrunk{}).Error(); err != nil {
+		t.Errorf("No error should happen when modify columns, but got %v", err)
+	}
+
+	if size, nullable := columnSize("name"); size != 32 || !nullable {
+		t.Errorf("Column name should be modified to nullable column of size 32, but got %v, %v", size, nullable)
+	}
+
+	if err := DB.Set("gorm:auto_migrate_modify_columns", true).AutoMigrate(&ColumnDrift{}).Error(); err != nil {
+		t.Errorf("No error should happen when modify columns, but got %v", err)
+	}
+
+	if size, _ := columnSize("name"); size != 64 {
+		t.Errorf("Growing columns should be applied without destructive flag, but got size %v", size)
+	}
+}
+
+type RenamedColumnUser struct {
+	ID    uint
+	Name  string `gorm:"size:64;index:idx_renamed_column_users_name"`
+	Email string `gorm:"not null"`
+}
+
+func TestRenameAndAlterColumns(t *testing.T) {
+	DB.DropTableIfExists(&RenamedColumnUser{}, "renamed_column_people")
+	DB.AutoMigrate(&RenamedColumnUser{})
+	DB.Create(&RenamedColumnUser{Name: "jinzhu", Email: "jinzhu@example.org"})
+
+	if err := DB.Model(&RenamedColumnUser{}).AlterColumnNullability("Email", true).Error(); err != nil {
+		t.Fatalf("No error should happen when alter nullability, but got %v", err)
+	}
+
+	if err := DB.Create(&RenamedColumnUser{Name: "nil"}).Error(); err != nil {
+		t.Errorf("No error should happen when create with nullable column, but got %v", err)
+	}
+	DB.Model(&RenamedColumnUser{}).Where("name = ?", "nil").UpdateColumn("email", gorm.Expr("NULL"))
+
+	var count int
+	if DB.Model(&RenamedColumnUser{}).Where("email IS NULL").Count(&count); count != 1 {
+		t.Errorf("Column email should be nullable, but got %v null emails", count)
+	}
+
+	if !DB.Dialect().HasIndex("renamed_column_users", "idx_renamed_column_users_name") {
+		t.Errorf("Indexes should be kept after altering columns")
+	}
+
+	if err := DB.Model(&RenamedColumnUser{}).RenameColumn("name", "full_name").Error(); err != nil {
+		t.Fatalf("No error should happen when rename column, but got %v", err)
+	}
+
+	if columnTypes, _ := DB.Dialect().ColumnTypes("renamed_column_users"); len(columnTypes) != 3 || columnTypes[1].Name != "full_name" {
+		t.Errorf("Column name should be renamed to full_name, but got %+v", columnTypes)
+	}
+
+	if err := DB.RenameTable("renamed_column_users", "renamed_column_people").Error(); err != nil {
+		t.Fatalf("No error should happen when rename table, but got %v", err)
+	}
+
+	var names []string
+	DB.Table("renamed_column_people").Order("id").Pluck("full_name", &names)
+	if DB.HasTable("renamed_column_users") || len(names) != 2 || names[0] != "jinzhu" {
+		t.Errorf("Table should be renamed with rows kept, but got %v", names)
+	}
+
+	DB.DropTableIfExists("renamed_column_people")
+
+	postgres, _ := gorm.Open("postgres", DB.SqlDB())
+	tx := postgres.DryRun(true).Model(&RenamedColumnUser{})
+	tx.AlterColumnNullability("email", false)
+	tx.RenameColumn("name", "full_name")
+	tx.RenameTable("renamed_column_users", "renamed_column_people")
+
+	expected := []string{
+		`ALTER TABLE "renamed_column_users" ALTER COLUMN "email" SET NOT NULL`,
+		`ALTER TABLE "renamed_column_users" RENAME COLUMN "name" TO "full_name"`,
+		`ALTER TABLE "renamed_column_users" RENAME TO "renamed_column_people"`,
+	}
+	var results []string
+	for _, statement := range tx.DryRunStatements() {
+		results = append(results, statement.SQL)
+	}
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should build statements for postgres, expects %v, but got %v", expected, results)
+	}
+
+	mysql, _ := gorm.Open("mysql", DB.SqlDB())
+	tx = mysql.DryRun(true).Model(&RenamedColumnUser{})
+	tx.AlterColumnNullability("email", true)
+
+	if statements := tx.DryRunStatements(); len(statements) != 1 || statements[0].SQL != "ALTER TABLE `renamed_column_users` MODIFY COLUMN `email` varchar(255) NULL" {
+		t.Errorf("Should modify column with its definition for mysql, but got %+v", statements)
+	}
+
+	mssql, _ := gorm.Open("mssql", DB.SqlDB())
+	tx = mssql.DryRun(true).Model(&RenamedColumnUser{})
+	tx.RenameColumn("name", "full_name")
+	tx.RenameTable("renamed_column_users", "renamed_column_people")
+
+	expectedVars := [][]interface{}{{"renamed_column_users.name", "full_name"}, {"renamed_column_users", "renamed_column_people"}}
+	var vars [][]interface{}
+	for _, statement := range tx.DryRunStatements() {
+		vars = append(vars, statement.Vars)
+	}
+
+	if !reflect.DeepEqual(vars, expectedVars) {
+		t.Errorf("Should rename with unquoted names for mssql, expects %v, but got %v", expectedVars, vars)
+	}
+}
+
+type DryRunModel struct {
+	ID   uint
+	Name string `sql:"index"`
+}
+
+func TestDryRun(t *testing.T) {
+	DB.DropTableIfExists(&DryRunModel{})
+
+	tx := DB.DryRun(true)
+	if err := tx.AutoMigrate(&DryRunModel{}).Error(); err != nil {
+		t.Fatalf("No error should happen in dry run mode, but got %v", err)
+	}
+
+	user := User{Name: "dry_run"}
+	tx.Create(&user)
+	tx.Model(&User{}).Where("name = ?", "dry_run").Update("age", 20)
+	tx.Where("name = ?", "dry_run").Delete(&User{})
+
+	statements := tx.DryRunStatements()
+	if len(statements) < 5 {
+		t.Fatalf("Should record all statements, but got %v", statements)
+	}
+
+	var hasCreateTable, hasCreateIndex, hasUpdate bool
+	for _, statement := range statements {
+		hasCreateTable = hasCreateTable || strings.HasPrefix(statement.SQL, "CREATE TABLE")
+		hasCreateIndex = hasCreateIndex || strings.HasPrefix(statement.SQL, "CREATE INDEX")
+		if strings.HasPrefix(statement.SQL, "UPDATE") {
+			hasUpdate = len(statement.Vars) > 0 && strings.Contains(statement.Explained, "'dry_run'")
+		}
+	}
+
+	if !hasCreateTable || !hasCreateIndex || !hasUpdate {
+		t.Errorf("Should record migration and update statements, but got %v", statements)
+	}
+
+	if DB.HasTable(&DryRunModel{}) {
+		t.Errorf("Table should not be created in dry run mode")
+	}
+
+	if !DB.Where("name = ?", "dry_run").First(&User{}).RecordNotFound() {
+		t.Errorf("Record should not be created in dry run mode")
+	}
+
+	if err := tx.DryRun(false).AutoMigrate(&DryRunModel{}).Error(); err != nil || !DB.HasTable(&DryRunModel{}) {
+		t.Errorf("Statements should be executed after dry run mode is disabled, but got %v", err)
+	}
+}
+
+type MigrationPlanModel struct {
+	ID   uint
+	Name string `sql:"index"`
+}
+
+type MigrationPlanModelV2 struct {
+	ID    uint
+	Name  string `sql:"index"`
+	Email string
+}
+
+func (MigrationPlanModelV2) TableName() string {
+	return "migration_plan_models"
+}
+
+func TestMigrationPlan(t *testing.T) {
+	DB.DropTableIfExists(&MigrationPlanModel{})
+
+	statements, err := DB.MigrationPlan(&MigrationPlanModel{})
+	if err != nil {
+		t.Fatalf("No error should happen when plan migration, but got %v", err)
+	}
+
+	if len(statements) != 2 || !strings.HasPrefix(statements[0], "CREATE TABLE") || !strings.HasPrefix(statements[1], "CREATE INDEX") {
+		t.Errorf("Should plan to create table and index, but got %v", statements)
+	}
+
+	if DB.HasTable(&MigrationPlanModel{}) {
+		t.Errorf("Table should not be created when plan migration")
+	}
+
+	DB.AutoMigrate(&MigrationPlanModel{})
+	if statements, err := DB.MigrationPlan(&MigrationPlanModel{}); err != nil || len(statements) != 0 {
+		t.Errorf("Should plan nothing for migrated model, but got %v, %v", statements, err)
+	}
+
+	statements, _ = DB.MigrationPlan(&MigrationPlanModelV2{})
+	if len(statements) != 1 || !strings.HasPrefix(statements[0], "ALTER TABLE") || !strings.Contains(statements[0], "email") {
+		t.Errorf("Should plan to add missing column, but got %v", statements)
+	}
+
+	if DB.Dialect().HasColumn("migration_plan_models", "email") {
+		t.Errorf("Column should not be added when plan migration")
+	}
+}
+
+type ForeignKeyCompany struct {
+	ID   uint
+	Name string
+}
+
+type ForeignKeyUser struct {
+	ID        uint
+	CompanyID uint
+	Company   ForeignKeyCompany `gorm:"constraint:OnDelete:CASCADE,OnUpdate:SET NULL"`
+	Emails    []ForeignKeyEmail
+	Languages []ForeignKeyLanguage `gorm:"many2many:foreign_key_user_languages"`
+	Toys      []ForeignKeyToy      `gorm:"polymorphic:Owner"`
+}
+
+type ForeignKeyEmail struct {
+	ID               uint
+	ForeignKeyUserID uint
+	Email            string
+}
+
+type ForeignKeyLanguage struct {
+	ID   uint
+	Name string
+}
+
+type ForeignKeyToy struct {
+	ID        uint
+	OwnerID   uint
+	OwnerType string
+}
+
+func TestAutoMigrateForeignKeys(t *testing.T) {
+	models := []interface{}{&ForeignKeyCompany{}, &ForeignKeyUser{}, &ForeignKeyEmail{}, &ForeignKeyLanguage{}, &ForeignKeyToy{}}
+
+	postgres, err := gorm.Open("postgres", DB.SqlDB())
+	if err != nil {
+		t.Fatalf("No error should happen when open db, but got %v", err)
+	}
+
+	foreignKeys := func(tx gorm.Repository) (results []string) {
+		for _, statement := range tx.DryRunStatements() {
+			if strings.Contains(statement.SQL, "FOREIGN KEY") {
+				results = append(results, statement.SQL)
+			}
+		}
+		return
+	}
+
+	tx := postgres.DryRun(true)
+	if err := tx.AutoMigrate(models...).Error(); err != nil {
+		t.Fatalf("No error should happen when auto migrate, but got %v", err)
+	}
+
+	expected := []string{
+		`ALTER TABLE "foreign_key_users" ADD CONSTRAINT foreign_key_users_company_id_foreign_key_companies_id_foreign FOREIGN KEY (company_id) REFERENCES "foreign_key_companies"("id") ON DELETE CASCADE ON UPDATE SET NULL;`,
+		`ALTER TABLE "foreign_key_emails" ADD CONSTRAINT foreign_key_emails_foreign_key_user_id_foreign_key_users_id_foreign FOREIGN KEY (foreign_key_user_id) REFERENCES "foreign_key_users"("id") ON DELETE NO ACTION ON UPDATE NO ACTION;`,
+		`ALTER TABLE "foreign_key_user_languages" ADD CONSTRAINT foreign_key_user_languages_foreign_key_user_id_foreign_key_users_id_foreign FOREIGN KEY (foreign_key_user_id) REFERENCES "foreign_key_users"("id") ON DELETE NO ACTION ON UPDATE NO ACTION;`,
+		`ALTER TABLE "foreign_key_user_languages" ADD CONSTRAINT foreign_key_user_languages_foreign_key_language_id_foreign_key_languages_id_foreign FOREIGN KEY (foreign_key_language_id) REFERENCES "foreign_key_languages"("id") ON DELETE NO ACTION ON UPDATE NO ACTION;`,
+	}
+
+	if results := foreignKeys(tx); !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should add foreign keys of relationships except polymorphic ones, but got\n%v", strings.Join(results, "\n"))
+	}
+
+	tx = postgres.DryRun(true).Set("gorm:auto_foreign_keys", false)
+	if tx.AutoMigrate(models...); len(foreignKeys(tx)) != 0 {
+		t.Errorf("Should not add foreign keys when disabled, but got %v", foreignKeys(tx))
+	}
+
+	if err := DB.AutoMigrate(models...).Error(); err != nil {
+		t.Errorf("Should skip foreign keys for dialects not supporting them, but got %v", err)
+	}
+}
+
+type IndexOptionsUser struct {
+	ID        uint
+	Name      string `gorm:"index:idx_index_options_users_age_name,priority:2"`
+	Age       int    `gorm:"index:idx_index_options_users_age_name,priority:1,sort:desc"`
+	Email     string `gorm:"unique_index:uix_index_options_users_email,expression:lower(email),where:deleted_at IS NULL"`
+	Code      string `gorm:"index:,type:hash"`
+	DeletedAt *time.Time
+}
+
+func TestAutoMigrateIndexOptions(t *testing.T) {
+	DB.DropTableIfExists(&IndexOptionsUser{})
+
+	indexes := func(tx gorm.Repository) (results []string) {
+		tx.AutoMigrate(&IndexOptionsUser{})
+		for _, statement := range tx.DryRunStatements() {
+			if strings.HasPrefix(statement.SQL, "CREATE") && strings.Contains(statement.SQL, "INDEX") {
+				results = append(results, statement.SQL)
+			}
+		}
+		return
+	}
+
+	expected := []string{
+		`CREATE INDEX idx_index_options_users_age_name ON "index_options_users"("age" DESC, "name")`,
+		`CREATE UNIQUE INDEX uix_index_options_users_email ON "index_options_users"(lower(email)) WHERE deleted_at IS NULL`,
+		`CREATE INDEX idx_index_options_users_code ON "index_options_users"("code")`,
+	}
+	if results := indexes(DB.DryRun(true)); !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should create indexes with options, but got\n%v", strings.Join(results, "\n"))
+	}
+
+	postgres, _ := gorm.Open("postgres", DB.SqlDB())
+	expected[2] = `CREATE INDEX idx_index_options_users_code ON "index_options_users" USING hash("code")`
+	if results := indexes(postgres.DryRun(true)); !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should create indexes with options for postgres, but got\n%v", strings.Join(results, "\n"))
+	}
+
+	mysql, _ := gorm.Open("mysql", DB.SqlDB())
+	if err := mysql.DryRun(true).AutoMigrate(&IndexOptionsUser{}).Error(); err == nil || !strings.Contains(err.Error(), "partial indexes are not supported") {
+		t.Errorf("Should return error for partial index in mysql, but got %v", err)
+	}
+
+	fulltext := gorm.Index{Name: "idx_name", Unique: true, Type: "fulltext", Columns: []gorm.IndexColumn{{Name: "`name`"}}}
+	if _, err := mysql.Dialect().CreateIndexSQL("`index_options_users`", fulltext); err == nil {
+		t.Errorf("Should return error for unique fulltext index in mysql")
+	}
+
+	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mysql" {
+		t.Skip("Skipping this because mysql doesn't support partial indexes")
+	}
+
+	if err := DB.AutoMigrate(&IndexOptionsUser{}).Error(); err != nil {
+		t.Fatalf("No error should happen when auto migrate, but got %v", err)
+	}
+
+	user := IndexOptionsUser{Name: "index", Email: "Index@example.org"}
+	DB.Save(&user)
+	if err := DB.Save(&IndexOptionsUser{Name: "index", Email: "index@example.org"}).Error(); err == nil {
+		t.Errorf("Should get error for duplicated lower case email")
+	}
+
+	DB.Delete(&user)
+	if err := DB.Save(&IndexOptionsUser{Name: "index", Email: "index@example.org"}).Error(); err != nil {
+		t.Errorf("Deleted users should be excluded from partial index, but got %v", err)
+	}
+}
+
+type CommentedProduct struct {
+	ID    uint
+	Code  string `gorm:"comment:stock keeping unit"`
+	Price int    `gorm:"check:price >= 0"`
+	Stock int    `gorm:"check:chk_commented_products_stock,stock >= 0 AND stock < 10000"`
+}
+
+func (CommentedProduct) TableComment() string {
+	return "products for sale"
+}
+
+func TestAutoMigrateChecksAndComments(t *testing.T) {
+	DB.DropTableIfExists(&CommentedProduct{})
+
+	statements := func(dialect string) (results []string) {
+		db, _ := gorm.Open(dialect, DB.SqlDB())
+		tx := db.DryRun(true)
+		tx.AutoMigrate(&CommentedProduct{})
+		for _, statement := range tx.DryRunStatements() {
+			if !strings.HasPrefix(statement.SQL, "SELECT") {
+				results = append(results, statement.SQL)
+			}
+		}
+		return
+	}
+
+	expected := []string{
+		`CREATE TABLE "commented_products" ("id" serial,"code" text,"price" integer,"stock" integer,CONSTRAINT "chk_commented_products_price" CHECK (price >= 0),CONSTRAINT "chk_commented_products_stock" CHECK (stock >= 0 AND stock < 10000) , PRIMARY KEY ("id"))`,
+		`COMMENT ON TABLE "commented_products" IS 'products for sale'`,
+		`COMMENT ON COLUMN "commented_products"."code" IS 'stock keeping unit'`,
+	}
+	if results := statements("postgres"); !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should create table with checks and comments for postgres, but got\n%v", strings.Join(results, "\n"))
+	}
+
+	expected = []string{
+		"CREATE TABLE `commented_products` (`id` int unsigned AUTO_INCREMENT,`code` varchar(255) COMMENT 'stock keeping unit',`price` int,`stock` int,CONSTRAINT `chk_commented_products_price` CHECK (price >= 0),CONSTRAINT `chk_commented_products_stock` CHECK (stock >= 0 AND stock < 10000) , PRIMARY KEY (`id`))",
+		"ALTER TABLE `commented_products` COMMENT = 'products for sale'",
+	}
+	if results := statements("mysql"); !reflect.DeepEqual(results, expected) {
+		t.Errorf("Should create table with checks and inline comments for mysql, but got\n%v", strings.Join(results, "\n"))
+	}
+
+	if err := DB.AutoMigrate(&CommentedProduct{}).Error(); err != nil {
+		t.Fatalf("No error should happen when auto migrate, but got %v", err)
+	}
+
+	if !DB.Dialect().SupportCheckConstraint() {
+		t.Skip("check constraints are ignored by this database")
+	}
+
+	if !DB.Dialect().HasConstraint("commented_products", "chk_commented_products_stock") {
+		t.Errorf("Should have check constraint chk_commented_products_stock")
+	}
+
+	if err := DB.Save(&CommentedProduct{Code: "P1", Price: 10, Stock: 1}).Error(); err != nil {
+		t.Errorf("No error should happen when save valid product, but got %v", err)
+	}
+
+	if err := DB.Save(&CommentedProduct{Code: "P2", Price: -1, Stock: 1}).Error(); err == nil {
+		t.Errorf("Should get error when violating check constraint")
+	}
+
+	if err := DB.AutoMigrate(&CommentedProduct{}).Error(); err != nil {
+		t.Errorf("No error should happen when auto migrate existing table, but got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoMigrationToRollback no applied migration could be rolled back when calling `RollbackLast`
+var ErrNoMigrationToRollback = errors.New("no migration to rollback")
+
+// Migration a versioned schema change, registered to a Migrator
+type Migration struct {
+	// ID unique id of the migration, e.g. `201901021504_create_users`
+	ID string
+	// Migrate apply the change, it runs inside a transaction
+	Migrate func(tx Repository) error
+	// Rollback revert the change, it runs inside a transaction, a migration without Rollback can't be rolled back
+	Rollback func(tx Repository) error
+}
+
+// Migrator run migrations in order of registration, and persist IDs of applied migrations in table `schema_migrations`
+//     migrator := gorm.NewMigrator(db,
+//         &gorm.Migration{ID: "201901021504_create_users", Migrate: func(tx gorm.Repository) error {
+//             return tx.CreateTable(&User{}).Error()
+//         }, Rollback: func(tx gorm.Repository) error {
+//             return tx.DropTable("users").Error()
+//         }},
+//     )
+//     err := migrator.Migrate()
+type Migrator struct {
+	db         Repository
+	migrations []*Migration
+}
+
+// schemaMigration a row of `schema_migrations`
+type schemaMigration struct {
+	ID        string `gorm:"primary_key;size:255"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// NewMigrator create a Migrator running migrations with db
+func NewMigrator(db Repository, migrations ...*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Register append migrations, they run after already registered ones
+func (m *Migrator) Register(migrations ...*Migration) *Migrator {
+	m.migrations = append(m.migrations, migrations...)
+	return m
+}
+
+// Migrate run all pending migrations
+func (m *Migrator) Migrate() error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.MigrateTo(m.migrations[len(m.migrations)-1].ID)
+}
+
+// MigrateTo run pending migrations registered before the migration with id, including itself
+func (m *Migrator) MigrateTo(id string) error {
+	applied, err := m.prepare()
+	if err != nil {
+		return err
+	}
+
+	if m.find(id) == nil {
+		return fmt.Errorf("gorm: migration %v not found", id)
+	}
+
+	for _, migration := range m.migrations {
+		if !applied[migration.ID] {
+			if err := m.run(migration); err != nil {
+				return err
+			}
+		}
+
+		if migration.ID == id {
+			break
+		}
+	}
+	return nil
+}
+
+// RollbackLast rollback the last applied migration
+func (m *Migrator) RollbackLast() error {
+	applied, err := m.prepare()
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if migration := m.migrations[i]; applied[migration.ID] {
+			return m.rollback(migration)
+		}
+	}
+	return ErrNoMigrationToRollback
+}
+
+// Applied return IDs of applied migrations in order of registration
+func (m *Migrator) Applied() ([]string, error) {
+	applied, err := m.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, migration := range m.migrations {
+		if applied[migration.ID] {
+			ids = append(ids, migration.ID)
+		}
+	}
+	return ids, nil
+}
+
+// prepare validate registered migrations, create `schema_migrations` if not exist, and return IDs of applied migrations
+func (m *Migrator) prepare() (map[string]bool, error) {
+	registered := map[string]bool{}
+	for _, migration := range m.migrations {
+		if migration.ID == "" {
+			return nil, errors.New("gorm: migration id can't be blank")
+		}
+
+		if registered[migration.ID] {
+			return nil, fmt.Errorf("gorm: duplicated migration id %v", migration.ID)
+		}
+		registered[migration.ID] = true
+	}
+
+	if err := m.db.AutoMigrate(&schemaMigration{}).Error(); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := m.db.New().Find(&rows).Error(); err != nil {
+		return nil, err
+	}
+
+	applied := map[string]bool{}
+	for _, row := range rows {
+		applied[row.ID] = true
+	}
+	return applied, nil
+}
+
+func (m *Migrator) find(id string) *Migration {
+	for _, migration := range m.migrations {
+		if migration.ID == id {
+			return migration
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) run(migration *Migration) error {
+	if migration.Migrate == nil {
+		return fmt.Errorf("gorm: migration %v has no Migrate", migration.ID)
+	}
+
+	return m.db.Transaction(func(tx Repository) error {
+		if err := migration.Migrate(tx); err != nil {
+			return fmt.Errorf("gorm: failed to run migration %v, got %v", migration.ID, err)
+		}
+		return tx.New().Create(&schemaMigration{ID: migration.ID, AppliedAt: NowFunc()}).Error()
+	})
+}
+
+func (m *Migrator) rollback(migration *Migration) error {
+	if migration.Rollback == nil {
+		return fmt.Errorf("gorm: migration %v can't be rolled back", migration.ID)
+	}
+
+	return m.db.Transaction(func(tx Repository) error {
+		if err := migration.Rollback(tx); err != nil {
+			return fmt.Errorf("gorm: failed to rollback migration %v, got %v", migration.ID, err)
+		}
+		return tx.New().Delete(&schemaMigration{ID: migration.ID}).Error()
+	})
+}
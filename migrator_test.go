@@ -0,0 +1,71 @@
+package gorm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type MigratorPost struct {
+	ID    uint
+	Title string
+}
+
+func TestMigrator(t *testing.T) {
+	DB.DropTableIfExists("schema_migrations", &MigratorPost{})
+
+	migrator := gorm.NewMigrator(DB,
+		&gorm.Migration{ID: "1_create_posts", Migrate: func(tx gorm.Repository) error {
+			return tx.CreateTable(&MigratorPost{}).Error()
+		}, Rollback: func(tx gorm.Repository) error {
+			return tx.DropTable(&MigratorPost{}).Error()
+		}},
+		&gorm.Migration{ID: "2_insert_post", Migrate: func(tx gorm.Repository) error {
+			return tx.Create(&MigratorPost{Title: "migrated"}).Error()
+		}, Rollback: func(tx gorm.Repository) error {
+			return tx.Where("title = ?", "migrated").Delete(&MigratorPost{}).Error()
+		}},
+	)
+
+	if err := migrator.MigrateTo("1_create_posts"); err != nil {
+		t.Fatalf("No error should happen when migrate, but got %v", err)
+	}
+
+	if applied, _ := migrator.Applied(); len(applied) != 1 || !DB.HasTable(&MigratorPost{}) {
+		t.Errorf("Should only apply the first migration, but got %v", applied)
+	}
+
+	migrator.Register(&gorm.Migration{ID: "3_failed", Migrate: func(tx gorm.Repository) error {
+		tx.Create(&MigratorPost{Title: "failed"})
+		return errors.New("failed")
+	}})
+
+	if err := migrator.Migrate(); err == nil {
+		t.Errorf("Should return error of the failed migration")
+	}
+
+	var count int
+	DB.Model(&MigratorPost{}).Count(&count)
+	if applied, _ := migrator.Applied(); len(applied) != 2 || count != 1 {
+		t.Errorf("Failed migration should be rolled back, but got %v applied, %v posts", applied, count)
+	}
+
+	if err := migrator.RollbackLast(); err != nil {
+		t.Errorf("No error should happen when rollback, but got %v", err)
+	}
+
+	DB.Model(&MigratorPost{}).Count(&count)
+	if applied, _ := migrator.Applied(); len(applied) != 1 || count != 0 {
+		t.Errorf("Should rollback the last migration, but got %v applied, %v posts", applied, count)
+	}
+
+	migrator.RollbackLast()
+	if err := migrator.RollbackLast(); err != gorm.ErrNoMigrationToRollback || DB.HasTable(&MigratorPost{}) {
+		t.Errorf("Should rollback all migrations, but got %v", err)
+	}
+
+	if err := gorm.NewMigrator(DB, &gorm.Migration{ID: "1"}, &gorm.Migration{ID: "1"}).Migrate(); err == nil {
+		t.Errorf("Should return error for duplicated migration id")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"go/ast"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -611,6 +613,58 @@ func (scope *Scope) GetStructFields() (fields []*StructField) {
 	return scope.GetModelStruct().StructFields
 }
 
+// indexTagSetting an index a field belongs to, refer `parseIndexTagSetting`
+type indexTagSetting struct {
+	Name       string
+	Priority   int
+	Sort       string
+	Where      string
+	Type       string
+	Expression string
+}
+
+// parseIndexTagSetting parse tag setting `INDEX` or `UNIQUE_INDEX`, which is a comma separated list of index names, each name could be
+// followed by options of the index, e.g. `idx_name,sort:desc,priority:1,idx_name_age,where:age > 18,type:btree,expression:lower(name)`;
+// columns are ordered by priority (default to 10) in an index, `where` and `type` apply to the whole index, `expression` replaces the column;
+// as options are separated by commas, values of `where` and `expression` can't contain commas, create such indexes with `Exec` instead
+func parseIndexTagSetting(str string) (settings []indexTagSetting) {
+	for _, option := range strings.Split(str, ",") {
+		values := strings.SplitN(option, ":", 2)
+		if len(values) == 1 || len(settings) == 0 {
+			settings = append(settings, indexTagSetting{Priority: 10})
+			if len(values) == 1 {
+				settings[len(settings)-1].Name = strings.TrimSpace(option)
+				continue
+			}
+		}
+
+		setting, value := &settings[len(settings)-1], strings.TrimSpace(values[1])
+		switch strings.ToUpper(strings.TrimSpace(values[0])) {
+		case "PRIORITY":
+			setting.Priority, _ = strconv.Atoi(value)
+		case "SORT":
+			setting.Sort = strings.ToUpper(value)
+		case "WHERE":
+			setting.Where = value
+		case "TYPE":
+			setting.Type = value
+		case "EXPRESSION":
+			setting.Expression = value
+		}
+	}
+	return
+}
+
+var identifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// parseCheckTagSetting parse tag setting `CHECK` like `age > 13` or `chk_age,age > 13`, which names the constraint
+func parseCheckTagSetting(str string) (name string, expression string) {
+	if values := strings.SplitN(str, ",", 2); len(values) == 2 && identifierRegexp.MatchString(strings.TrimSpace(values[0])) {
+		return strings.TrimSpace(values[0]), strings.TrimSpace(values[1])
+	}
+	return "", strings.TrimSpace(str)
+}
+
 func parseTagSetting(tags reflect.StructTag) map[string]string {
 	setting := map[string]string{}
 	for _, str := range []string{tags.Get("sql"), tags.Get("gorm")} {
@@ -3,8 +3,10 @@ package gorm_test
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"gorm.io/gorm"
@@ -1669,6 +1671,141 @@ func TestPreloadManyToManyCallbacks(t *testing.T) {
 	}
 }
 
+func TestPreloadWithOptions(t *testing.T) {
+	user1 := User{
+		Name:      "preload_options_user1",
+		Emails:    []Email{{Email: "preload_options1@a.com"}, {Email: "preload_options1@b.com"}, {Email: "preload_options1@c.com"}},
+		Languages: []Language{{Name: "preload_options_ZH"}, {Name: "preload_options_EN"}, {Name: "preload_options_DE"}},
+	}
+	user2 := User{
+		Name:      "preload_options_user2",
+		Emails:    []Email{{Email: "preload_options2@a.com"}, {Email: "preload_options2@b.com"}},
+		Languages: []Language{{Name: "preload_options_FR"}},
+	}
+	DB.Save(&user1).Save(&user2)
+	DB.Delete(&user1.Languages[0])
+
+	var users []User
+	DB.Where("name LIKE ?", "preload_options_user%").Order("id").Preload("Emails", gorm.PreloadOptions{Order: "email DESC", Limit: 2}).Find(&users)
+	if len(users) != 2 || len(users[0].Emails) != 2 || users[0].Emails[0].Email != "preload_options1@c.com" || users[0].Emails[1].Email != "preload_options1@b.com" || len(users[1].Emails) != 2 {
+		t.Errorf("Should preload limited ordered emails of each user, but got %+v", users)
+	}
+
+	DB.Where("name LIKE ?", "preload_options_user%").Order("id").Preload("Emails", gorm.PreloadOptions{Select: []string{"id"}}, "email LIKE ?", "%@a.com").Find(&users)
+	if len(users[0].Emails) != 1 || users[0].Emails[0].Id == 0 || int64(users[0].Emails[0].UserId) != user1.Id || users[0].Emails[0].Email != "" {
+		t.Errorf("Should preload selected columns and foreign keys, but got %+v", users[0].Emails)
+	}
+
+	DB.Where("name LIKE ?", "preload_options_user%").Order("id").Preload("Languages", gorm.PreloadOptions{Order: "name", Limit: 1}).Find(&users)
+	if len(users[0].Languages) != 1 || users[0].Languages[0].Name != "preload_options_DE" || len(users[1].Languages) != 1 {
+		t.Errorf("Should preload limited many2many records without soft deleted records, but got %+v", users)
+	}
+
+	DB.Where("name LIKE ?", "preload_options_user%").Order("id").Preload("Languages", &gorm.PreloadOptions{Order: "name DESC", Select: []string{"name"}, Unscoped: true}).Find(&users)
+	if len(users[0].Languages) != 3 || users[0].Languages[0].Name != "preload_options_ZH" || users[0].Languages[0].ID != 0 {
+		t.Errorf("Should preload soft deleted many2many records when unscoped, but got %+v", users[0].Languages)
+	}
+
+	var user User
+	DB.Preload("Emails", gorm.PreloadOptions{Order: "email", Limit: 1}).First(&user, user1.Id)
+	if len(user.Emails) != 1 || user.Emails[0].Email != "preload_options1@a.com" {
+		t.Errorf("Should preload limited emails of a user, but got %+v", user.Emails)
+	}
+
+	counting := &versionCountingDB{SQLCommon: DB.SqlDB()}
+	countingDB, _ := gorm.Open(DB.Dialect().GetName(), counting)
+	for i := 0; i < 3; i++ {
+		countingDB.Where("name LIKE ?", "preload_options_user%").Preload("Emails", gorm.PreloadOptions{Limit: 1}).Find(&users)
+	}
+	if counting.versionQueries > 1 {
+		t.Errorf("Should query version of database once, but got %v queries", counting.versionQueries)
+	}
+}
+
+// versionCountingDB count queries of database version
+type versionCountingDB struct {
+	gorm.SQLCommon
+	versionQueries int
+}
+
+func (db *versionCountingDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	if strings.Contains(strings.ToLower(query), "version") {
+		db.versionQueries++
+	}
+	return db.SQLCommon.QueryRow(query, args...)
+}
+
+type ChunkedPreloadOwner struct {
+	ID   uint
+	Name string
+}
+
+type ChunkedPreloadItem struct {
+	ID      uint
+	OwnerID uint
+	Owner   ChunkedPreloadOwner
+	Notes   []ChunkedPreloadNote
+	Tags    []ChunkedPreloadTag `gorm:"many2many:chunked_preload_item_tags"`
+}
+
+type ChunkedPreloadNote struct {
+	ID                   uint
+	ChunkedPreloadItemID uint
+	Body                 string
+}
+
+type ChunkedPreloadTag struct {
+	ID   uint
+	Name string
+}
+
+func TestPreloadInChunks(t *testing.T) {
+	DB.DropTableIfExists(&ChunkedPreloadOwner{}, &ChunkedPreloadItem{}, &ChunkedPreloadNote{}, &ChunkedPreloadTag{}, "chunked_preload_item_tags")
+	DB.AutoMigrate(&ChunkedPreloadOwner{}, &ChunkedPreloadItem{}, &ChunkedPreloadNote{}, &ChunkedPreloadTag{})
+
+	var (
+		count  = 1200
+		owners []ChunkedPreloadOwner
+		items  []ChunkedPreloadItem
+		notes  []ChunkedPreloadNote
+		tags   = []ChunkedPreloadTag{{ID: 1, Name: "odd"}, {ID: 2, Name: "even"}}
+	)
+	for i := 1; i <= count; i++ {
+		owners = append(owners, ChunkedPreloadOwner{ID: uint(i), Name: fmt.Sprintf("owner%v", i)})
+		items = append(items, ChunkedPreloadItem{ID: uint(i), OwnerID: uint(i)})
+		notes = append(notes, ChunkedPreloadNote{ID: uint(i), ChunkedPreloadItemID: uint(i), Body: fmt.Sprintf("note%v", i)})
+	}
+	DB.CreateInBatches(&owners, 300).CreateInBatches(&items, 300).CreateInBatches(&notes, 300).Create(&tags)
+	DB.Exec("INSERT INTO chunked_preload_item_tags (chunked_preload_item_id, chunked_preload_tag_id) SELECT id, id % 2 + 1 FROM chunked_preload_items")
+
+	var queries int
+	DB.Callback().Query().After("gorm:query").Register("test:count_chunked_preload", func(scope *gorm.Scope) {
+		if scope.TableName() == "chunked_preload_notes" {
+			queries++
+		}
+	})
+	defer DB.Callback().Query().Remove("test:count_chunked_preload")
+
+	var results []ChunkedPreloadItem
+	if err := DB.Preload("Owner").Preload("Notes").Preload("Tags").Order("id").Find(&results).Error(); err != nil {
+		t.Fatalf("No error should happen when preload more records than max bind vars, but got %v", err)
+	}
+
+	if len(results) != count {
+		t.Fatalf("Should find %v items, but got %v", count, len(results))
+	}
+
+	for _, item := range results {
+		if item.Owner.Name != fmt.Sprintf("owner%v", item.ID) || len(item.Notes) != 1 || item.Notes[0].Body != fmt.Sprintf("note%v", item.ID) || len(item.Tags) != 1 || item.Tags[0].ID != item.ID%2+1 {
+			t.Fatalf("Should preload associations of all items, but got %+v", item)
+		}
+	}
+
+	if maxBindVars := DB.Dialect().MaxBindVars(); maxBindVars > 0 && maxBindVars < count && queries < 2 {
+		t.Errorf("Should preload in chunks when keys exceed max bind vars, but got %v queries", queries)
+	}
+}
+
 func toJSONString(v interface{}) []byte {
 	r, _ := json.MarshalIndent(v, "", "  ")
 	return r
@@ -3,6 +3,7 @@ package gorm_test
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -470,6 +471,88 @@ func TestOr(t *testing.T) {
 	}
 }
 
+func TestGroupedConditions(t *testing.T) {
+	user1 := User{Name: "GroupUser1", Age: 1, Role: Role{Name: "group"}}
+	user2 := User{Name: "GroupUser2", Age: 10, Role: Role{Name: "group"}}
+	user3 := User{Name: "GroupUser3", Age: 20, Role: Role{Name: "other"}}
+	DB.Save(&user1).Save(&user2).Save(&user3)
+
+	var users []User
+	DB.Where(gorm.Or(gorm.Expr("name = ?", user1.Name), gorm.Expr("name = ?", user3.Name))).Where("role = ?", "group").Find(&users)
+	if len(users) != 1 || users[0].Name != user1.Name {
+		t.Errorf("Grouped or conditions should be combined with and, but got %v", users)
+	}
+
+	DB.Where("role = ?", "group").Where(func(db gorm.Repository) gorm.Repository {
+		return db.Where("name = ?", user2.Name).Or("name = ?", user3.Name)
+	}).Find(&users)
+	if len(users) != 1 || users[0].Name != user2.Name {
+		t.Errorf("Conditions of scope func should be grouped, but got %v", users)
+	}
+
+	DB.Where("name LIKE ?", "GroupUser%").Not(gorm.And("age > 5", map[string]interface{}{"role": "group"})).Order("age").Find(&users)
+	if len(users) != 2 || users[0].Name != user1.Name || users[1].Name != user3.Name {
+		t.Errorf("Not should negate grouped conditions, but got %v", users)
+	}
+
+	tenant := DB.Where("age > ?", 5).Or("role = ?", "group").Search()
+	DB.Where("name LIKE ?", "GroupUser%").Where(gorm.Not(tenant)).Find(&users)
+	if len(users) != 0 {
+		t.Errorf("Conditions of search should be grouped, but got %v", users)
+	}
+
+	sql := DB.ToSQL(func(tx gorm.Repository) gorm.Repository {
+		return tx.Where(gorm.Or("age = 1", gorm.And("age > 5", "age < 15"))).Where(gorm.Not()).Find(&[]User{})
+	})
+	if !strings.Contains(sql, "WHERE ((age = 1) OR ((age > 5) AND (age < 15)))") {
+		t.Errorf("Grouped conditions should be rendered in parentheses, but got %v", sql)
+	}
+}
+
+func TestColumnConditions(t *testing.T) {
+	user1 := User{Name: "ColumnUser1", Age: 1}
+	user2 := User{Name: "ColumnUser2", Age: 10}
+	user3 := User{Name: "ColumnUser3", Age: 20}
+	DB.Save(&user1).Save(&user2).Save(&user3)
+
+	var users []User
+	DB.Where(gorm.Col("name").Like("ColumnUser%")).Where(gorm.Col("Age").Gt(5)).Order("age").Find(&users)
+	if len(users) != 2 || users[0].Name != user2.Name {
+		t.Errorf("Should find users with column conditions, but got %v", users)
+	}
+
+	DB.Where(gorm.Col("age").Between(1, 10)).Where(gorm.Col("name").In([]string{user1.Name, user3.Name})).Find(&users)
+	if len(users) != 1 || users[0].Name != user1.Name {
+		t.Errorf("Should find users with between and in conditions, but got %v", users)
+	}
+
+	DB.Where(gorm.Col("users.name").Like("ColumnUser%")).Where(gorm.Or(gorm.Col("age").Lte(1), gorm.Col("age").Eq(20))).Not(gorm.Col("birthday").IsNotNull()).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find users with column conditions in groups, but got %v", users)
+	}
+
+	subQuery := DB.Table("users").Select("id").Where("name = ?", user2.Name).SubQuery()
+	DB.Where(gorm.Col("id").In(subQuery)).Find(&users)
+	if len(users) != 1 || users[0].Name != user2.Name {
+		t.Errorf("Should find users with sub query, but got %v", users)
+	}
+
+	if err := DB.Where(gorm.Col("nmae").Eq("jinzhu")).Find(&users).Error(); err == nil {
+		t.Errorf("Should return error for unknown column")
+	}
+
+	if err := DB.Where(gorm.Col(`companies.name"; --`).Eq("jinzhu")).Find(&users).Error(); err == nil {
+		t.Errorf("Should return error for invalid column")
+	}
+
+	sql := DB.ToSQL(func(tx gorm.Repository) gorm.Repository {
+		return tx.Where(gorm.Col("Age").Gte(18)).Find(&[]User{})
+	})
+	if !strings.Contains(sql, fmt.Sprintf("(%v.%v >= 18)", DB.Dialect().Quote("users"), DB.Dialect().Quote("age"))) {
+		t.Errorf("Column should be quoted, but got %v", sql)
+	}
+}
+
 func TestCount(t *testing.T) {
 	user1 := User{Name: "CountUser1", Age: 1}
 	user2 := User{Name: "CountUser2", Age: 10}
@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -22,6 +23,7 @@ type Scope struct {
 	instanceID      string
 	primaryKeyField *Field
 	skipLeft        bool
+	traced          bool
 	fields          *[]*Field
 	selectAttrs     *[]string
 }
@@ -61,6 +63,11 @@ func (scope *Scope) SQLDB() SQLCommon {
 	return scope.db.SQLCommonDB()
 }
 
+// Context return the context of current operation, `context.Background()` if none was given with `WithContext`
+func (scope *Scope) Context() context.Context {
+	return scope.db.Context()
+}
+
 // Dialect get dialect
 func (scope *Scope) Dialect() Dialect {
 	return scope.db.Dialect()
@@ -82,7 +89,12 @@ func (scope *Scope) Quote(str string) string {
 // Err add error to Scope
 func (scope *Scope) Err(err error) error {
 	if err != nil {
-		scope.db.AddError(err)
+		if len(scope.SQL) > 0 && !scope.traced {
+			// the error will be logged with its SQL by `trace`
+			addError(scope.db, err)
+		} else {
+			scope.db.AddError(err)
+		}
 	}
 	return err
 }
@@ -97,6 +109,13 @@ func (scope *Scope) Log(v ...interface{}) {
 	scope.db.Log(v...)
 }
 
+// warn log message at warn level
+func (scope *Scope) warn(msg string, data ...interface{}) {
+	if logger := scope.db.ContextLogger(); logger != nil {
+		logger.Warn(scope.db.Context(), msg, data...)
+	}
+}
+
 // SkipLeft skip remaining callbacks
 func (scope *Scope) SkipLeft() {
 	scope.skipLeft = true
@@ -253,6 +272,25 @@ func (scope *Scope) CallMethod(methodName string) {
 	}
 }
 
+// elementScopes return a scope for every element if scope's value is a slice, otherwise return the scope itself,
+// element scopes share the db and search of scope
+func (scope *Scope) elementScopes() []*Scope {
+	indirectScopeValue := scope.IndirectValue()
+	if indirectScopeValue.Kind() != reflect.Slice {
+		return []*Scope{scope}
+	}
+
+	scopes := make([]*Scope, 0, indirectScopeValue.Len())
+	for i := 0; i < indirectScopeValue.Len(); i++ {
+		elem := indirectScopeValue.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		scopes = append(scopes, &Scope{db: scope.db, Search: scope.Search, Value: elem.Interface()})
+	}
+	return scopes
+}
+
 // AddToVars add value as sql's vars, used to prevent SQL injection
 func (scope *Scope) AddToVars(value interface{}) string {
 	_, skipBindVar := scope.InstanceGet("skip_bindvar")
@@ -310,6 +348,10 @@ type dbTabler interface {
 	TableName(repository Repository) string
 }
 
+type tableCommenter interface {
+	TableComment() string
+}
+
 // TableName return table name
 func (scope *Scope) TableName() string {
 	if scope.Search != nil && len(scope.Search.tableName) > 0 {
@@ -353,6 +395,7 @@ func (scope *Scope) CombinedConditionSql() string {
 // Raw set raw sql
 func (scope *Scope) Raw(sql string) *Scope {
 	scope.SQL = strings.Replace(sql, "$$$", "?", -1)
+	scope.traced = false
 	return scope
 }
 
@@ -360,8 +403,8 @@ func (scope *Scope) Raw(sql string) *Scope {
 func (scope *Scope) Exec() *Scope {
 	defer scope.trace(NowFunc())
 
-	if !scope.HasError() {
-		if result, err := scope.SQLDB().Exec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
+	if !scope.HasError() && !scope.recordDryRun() {
+		if result, err := scope.sqlExec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
 			if count, err := result.RowsAffected(); scope.Err(err) == nil {
 				scope.db.SetRowsAffected(count)
 			}
@@ -401,8 +444,8 @@ func (scope *Scope) InstanceGet(name string) (interface{}, bool) {
 
 // Begin start a transaction
 func (scope *Scope) Begin() *Scope {
-	if db, ok := scope.SQLDB().(sqlDb); ok {
-		if tx, err := db.Begin(); err == nil {
+	if db, ok := scope.SQLDB().(sqlDb); ok && !scope.isDryRun() {
+		if tx, err := scope.beginTx(db); err == nil {
 			scope.db.SetSQLCommonDB(interface{}(tx).(SQLCommon))
 			scope.InstanceSet("gorm:started_transaction", true)
 		}
@@ -429,6 +472,35 @@ func (scope *Scope) CommitOrRollback() *Scope {
 // Private Methods For *gorm.Scope
 ////////////////////////////////////////////////////////////////////////////////
 
+func (scope *Scope) beginTx(db sqlDb) (*sql.Tx, error) {
+	if dbCtx, ok := db.(sqlDbContext); ok {
+		return dbCtx.BeginTx(scope.Context(), nil)
+	}
+	return db.Begin()
+}
+
+// sqlExec, sqlQuery and sqlQueryRow run statements with the scope's context if the connection supports it
+func (scope *Scope) sqlExec(query string, args ...interface{}) (sql.Result, error) {
+	if db, ok := scope.SQLDB().(SQLCommonContext); ok {
+		return db.ExecContext(scope.Context(), query, args...)
+	}
+	return scope.SQLDB().Exec(query, args...)
+}
+
+func (scope *Scope) sqlQuery(query string, args ...interface{}) (*sql.Rows, error) {
+	if db, ok := scope.SQLDB().(SQLCommonContext); ok {
+		return db.QueryContext(scope.Context(), query, args...)
+	}
+	return scope.SQLDB().Query(query, args...)
+}
+
+func (scope *Scope) sqlQueryRow(query string, args ...interface{}) *sql.Row {
+	if db, ok := scope.SQLDB().(SQLCommonContext); ok {
+		return db.QueryRowContext(scope.Context(), query, args...)
+	}
+	return scope.SQLDB().QueryRow(query, args...)
+}
+
 func (scope *Scope) callMethod(methodName string, reflectValue reflect.Value) {
 	// Only get address from non-pointer
 	if reflectValue.CanAddr() && reflectValue.Kind() != reflect.Ptr {
@@ -480,8 +552,20 @@ func (scope *Scope) scan(rows *sql.Rows, columns []string, fields []*Field) {
 		selectFields       []*Field
 		selectedColumnsMap = map[string]int{}
 		resetFields        = map[int]*Field{}
+		joinedValues       = map[*Field]*joinedScanValue{}
 	)
 
+	scanInto := func(index int, field *Field) {
+		if field.Field.Kind() == reflect.Ptr {
+			values[index] = field.Field.Addr().Interface()
+		} else {
+			reflectValue := reflect.New(reflect.PtrTo(field.Struct.Type))
+			reflectValue.Elem().Set(field.Field.Addr())
+			values[index] = reflectValue.Interface()
+			resetFields[index] = field
+		}
+	}
+
 	for index, column := range columns {
 		values[index] = &ignored
 
@@ -490,24 +574,48 @@ func (scope *Scope) scan(rows *sql.Rows, columns []string, fields []*Field) {
 			selectFields = selectFields[idx+1:]
 		}
 
+		var matched bool
 		for fieldIndex, field := range selectFields {
 			if field.DBName == column {
-				if field.Field.Kind() == reflect.Ptr {
-					values[index] = field.Field.Addr().Interface()
-				} else {
-					reflectValue := reflect.New(reflect.PtrTo(field.Struct.Type))
-					reflectValue.Elem().Set(field.Field.Addr())
-					values[index] = reflectValue.Interface()
-					resetFields[index] = field
-				}
-
+				scanInto(index, field)
 				selectedColumnsMap[column] = fieldIndex
+				matched = true
 
 				if field.IsNormal {
 					break
 				}
 			}
 		}
+
+		// columns of joined relationships like `Company__name`, refer `relationshipJoinColumns`
+		if names := strings.SplitN(column, "__", 2); !matched && len(names) == 2 {
+			for _, field := range fields {
+				if field.Name != names[0] || field.Relationship == nil || (field.Relationship.Kind != "belongs_to" && field.Relationship.Kind != "has_one") {
+					continue
+				}
+
+				joined, ok := joinedValues[field]
+				if !ok {
+					joined = &joinedScanValue{}
+					if field.Field.Kind() == reflect.Ptr {
+						joined.value = reflect.New(field.Struct.Type.Elem())
+					} else {
+						joined.value = field.Field.Addr()
+					}
+					joined.fields = scope.New(joined.value.Interface()).Fields()
+					joinedValues[field] = joined
+				}
+
+				for _, joinedField := range joined.fields {
+					if joinedField.DBName == names[1] && joinedField.IsNormal {
+						scanInto(index, joinedField)
+						joined.indexes = append(joined.indexes, index)
+						break
+					}
+				}
+				break
+			}
+		}
 	}
 
 	scope.Err(rows.Scan(values...))
@@ -517,6 +625,25 @@ func (scope *Scope) scan(rows *sql.Rows, columns []string, fields []*Field) {
 			field.Field.Set(v)
 		}
 	}
+
+	// set pointers of joined relationships unless all their columns are null
+	for field, joined := range joinedValues {
+		if field.Field.Kind() == reflect.Ptr {
+			for _, index := range joined.indexes {
+				if !reflect.ValueOf(values[index]).Elem().IsNil() {
+					field.Field.Set(joined.value)
+					break
+				}
+			}
+		}
+	}
+}
+
+// joinedScanValue value of a joined relationship field being scanned
+type joinedScanValue struct {
+	value   reflect.Value
+	fields  []*Field
+	indexes []int
 }
 
 func (scope *Scope) primaryCondition(value interface{}) string {
@@ -578,6 +705,24 @@ func (scope *Scope) buildCondition(clause map[string]interface{}, include bool)
 			}
 		}
 		return strings.Join(sqls, " AND ")
+	case *Condition:
+		return scope.buildGroupCondition(value, include)
+	case *ColumnCondition:
+		return scope.buildColumnCondition(value, include)
+	case *Search:
+		return scope.buildSearchCondition(value, include)
+	case func(Repository) Repository:
+		return scope.buildSearchCondition(value(scope.NewDB()).Search(), include)
+	case *Expression:
+		if value.expr == "" {
+			return
+		}
+		if !include {
+			str = fmt.Sprintf("NOT (%v)", value.expr)
+		} else {
+			str = fmt.Sprintf("(%v)", value.expr)
+		}
+		clause["args"] = value.args
 	case interface{}:
 		var sqls []string
 		newScope := scope.New(value)
@@ -708,9 +853,9 @@ func (scope *Scope) buildSelectQuery(clause map[string]interface{}) (str string)
 
 func (scope *Scope) whereSQL() (sql string) {
 	var (
-		quotedTableName                                = scope.QuotedTableName()
-		deletedAtField, hasDeletedAtField              = scope.FieldByName("DeletedAt")
-		primaryConditions, andConditions, orConditions []string
+		quotedTableName                   = scope.QuotedTableName()
+		deletedAtField, hasDeletedAtField = scope.FieldByName("DeletedAt")
+		primaryConditions                 []string
 	)
 
 	if !scope.Search.Unscoped && hasDeletedAtField {
@@ -725,33 +870,7 @@ func (scope *Scope) whereSQL() (sql string) {
 		}
 	}
 
-	for _, clause := range scope.Search.whereConditions {
-		if sql := scope.buildCondition(clause, true); sql != "" {
-			andConditions = append(andConditions, sql)
-		}
-	}
-
-	for _, clause := range scope.Search.orConditions {
-		if sql := scope.buildCondition(clause, true); sql != "" {
-			orConditions = append(orConditions, sql)
-		}
-	}
-
-	for _, clause := range scope.Search.notConditions {
-		if sql := scope.buildCondition(clause, false); sql != "" {
-			andConditions = append(andConditions, sql)
-		}
-	}
-
-	orSQL := strings.Join(orConditions, " OR ")
-	combinedSQL := strings.Join(andConditions, " AND ")
-	if len(combinedSQL) > 0 {
-		if len(orSQL) > 0 {
-			combinedSQL = combinedSQL + " OR " + orSQL
-		}
-	} else {
-		combinedSQL = orSQL
-	}
+	combinedSQL := scope.searchConditionsSQL(scope.Search)
 
 	if len(primaryConditions) > 0 {
 		sql = "WHERE " + strings.Join(primaryConditions, " AND ")
@@ -767,13 +886,29 @@ func (scope *Scope) whereSQL() (sql string) {
 func (scope *Scope) selectSQL() string {
 	if len(scope.Search.selects) == 0 {
 		if len(scope.Search.joinConditions) > 0 {
-			return fmt.Sprintf("%v.*", scope.QuotedTableName())
+			return strings.Join(append([]string{fmt.Sprintf("%v.*", scope.QuotedTableName())}, scope.relationshipJoinColumns()...), ", ")
 		}
 		return "*"
 	}
 	return scope.buildSelectQuery(scope.Search.selects)
 }
 
+// relationshipJoinColumns return columns of joined belongs to and has one relationships, which are aliased like `Company__name` to
+// be scanned into the relationship field
+func (scope *Scope) relationshipJoinColumns() (columns []string) {
+	for _, clause := range scope.Search.joinConditions {
+		if field, ok := scope.relationshipJoinField(clause); ok && (field.Relationship.Kind == "belongs_to" || field.Relationship.Kind == "has_one") {
+			joinScope := scope.relationshipJoinScope(field)
+			for _, joinField := range joinScope.GetModelStruct().StructFields {
+				if joinField.IsNormal {
+					columns = append(columns, fmt.Sprintf("%v.%v AS %v", joinScope.QuotedTableName(), scope.Quote(joinField.DBName), scope.Quote(field.Name+"__"+joinField.DBName)))
+				}
+			}
+		}
+	}
+	return
+}
+
 func (scope *Scope) orderSQL() string {
 	if len(scope.Search.orders) == 0 || scope.Search.ignoreOrderQuery {
 		return ""
@@ -828,7 +963,15 @@ func (scope *Scope) havingSQL() string {
 func (scope *Scope) joinsSQL() string {
 	var joinConditions []string
 	for _, clause := range scope.Search.joinConditions {
-		if sql := scope.buildCondition(clause, true); sql != "" {
+		if field, ok := scope.relationshipJoinField(clause); ok {
+			joinType, _ := clause["join"].(string)
+			if joinType == "" {
+				joinType = "LEFT JOIN"
+			}
+			joinConditions = append(joinConditions, scope.relationshipJoinSQL(joinType, field, clause["args"].([]interface{})))
+		} else if _, ok := clause["join"]; ok {
+			scope.Err(fmt.Errorf("gorm: invalid relationship %v of %v", clause["query"], scope.TableName()))
+		} else if sql := scope.buildCondition(clause, true); sql != "" {
 			joinConditions = append(joinConditions, strings.TrimSuffix(strings.TrimPrefix(sql, "("), ")"))
 		}
 	}
@@ -836,6 +979,91 @@ func (scope *Scope) joinsSQL() string {
 	return strings.Join(joinConditions, " ") + " "
 }
 
+// relationshipJoinField return the relationship field of a join specified by its field name, refer `LeftJoins`
+func (scope *Scope) relationshipJoinField(clause map[string]interface{}) (*StructField, bool) {
+	name, _ := clause["query"].(string)
+	if !identifierRegexp.MatchString(name) {
+		return nil, false
+	}
+
+	for _, field := range scope.GetModelStruct().StructFields {
+		if field.Name == name && field.Relationship != nil {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// relationshipJoinScope return scope of the model of a joined relationship, which is aliased as the field name and shares vars with scope
+func (scope *Scope) relationshipJoinScope(field *StructField) *Scope {
+	reflectType := field.Struct.Type
+	for reflectType.Kind() == reflect.Slice || reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+
+	return &Scope{
+		db:         scope.db,
+		Search:     &Search{tableName: field.Name},
+		Value:      reflect.New(reflectType).Interface(),
+		SQLVars:    scope.SQLVars,
+		instanceID: scope.InstanceID(),
+	}
+}
+
+// relationshipJoinSQL build the JOIN clause of a relationship, many2many relationships join the join table first
+func (scope *Scope) relationshipJoinSQL(joinType string, field *StructField, args []interface{}) string {
+	var (
+		relationship    = field.Relationship
+		joinScope       = scope.relationshipJoinScope(field)
+		quotedTableName = scope.QuotedTableName()
+		quotedAlias     = joinScope.QuotedTableName()
+		conditions      []string
+		sql             string
+	)
+
+	switch relationship.Kind {
+	case "belongs_to":
+		for idx, foreignKey := range relationship.ForeignDBNames {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", quotedAlias, scope.Quote(relationship.AssociationForeignDBNames[idx]), quotedTableName, scope.Quote(foreignKey)))
+		}
+	case "has_one", "has_many":
+		for idx, foreignKey := range relationship.ForeignDBNames {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", quotedAlias, scope.Quote(foreignKey), quotedTableName, scope.Quote(relationship.AssociationForeignDBNames[idx])))
+		}
+
+		if relationship.PolymorphicType != "" {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v", quotedAlias, scope.Quote(relationship.PolymorphicDBName), joinScope.AddToVars(relationship.PolymorphicValue)))
+		}
+	case "many_to_many":
+		var (
+			handler          = relationship.JoinTableHandler
+			quotedJoinTable  = scope.Quote(handler.Table(scope.db))
+			joinTableMatches []string
+		)
+		for _, foreignKey := range handler.SourceForeignKeys() {
+			joinTableMatches = append(joinTableMatches, fmt.Sprintf("%v.%v = %v.%v", quotedJoinTable, scope.Quote(foreignKey.DBName), quotedTableName, scope.Quote(foreignKey.AssociationDBName)))
+		}
+		sql = fmt.Sprintf("%v %v ON %v ", joinType, quotedJoinTable, strings.Join(joinTableMatches, " AND "))
+
+		for _, foreignKey := range handler.DestinationForeignKeys() {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", quotedAlias, scope.Quote(foreignKey.AssociationDBName), quotedJoinTable, scope.Quote(foreignKey.DBName)))
+		}
+	}
+
+	if deletedAtField, ok := joinScope.FieldByName("DeletedAt"); ok && !scope.Search.Unscoped {
+		conditions = append(conditions, fmt.Sprintf("%v.%v IS NULL", quotedAlias, scope.Quote(deletedAtField.DBName)))
+	}
+
+	if len(args) > 0 {
+		if condition := joinScope.buildCondition(map[string]interface{}{"query": args[0], "args": args[1:]}, true); condition != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+	scope.SQLVars = joinScope.SQLVars
+
+	return sql + fmt.Sprintf("%v %v AS %v ON %v", joinType, scope.New(joinScope.Value).QuotedTableName(), quotedAlias, strings.Join(conditions, " AND "))
+}
+
 func (scope *Scope) prepareQuerySQL() {
 	if scope.Search.raw {
 		scope.Raw(scope.CombinedConditionSql())
@@ -979,7 +1207,7 @@ func (scope *Scope) pluck(column string, value interface{}) *Scope {
 	}
 
 	rows, err := scope.rows()
-	if scope.Err(err) == nil {
+	if scope.Err(err) == nil && rows != nil {
 		defer rows.Close()
 		for rows.Next() {
 			elem := reflect.New(dest.Type().Elem()).Interface()
@@ -1004,7 +1232,9 @@ func (scope *Scope) count(value interface{}) *Scope {
 		}
 	}
 	scope.Search.ignoreOrderQuery = true
-	scope.Err(scope.row().Scan(value))
+	if row := scope.row(); row != nil {
+		scope.Err(row.Scan(value))
+	}
 	return scope
 }
 
@@ -1021,6 +1251,7 @@ func (scope *Scope) typeName() string {
 // trace print sql log
 func (scope *Scope) trace(t time.Time) {
 	if len(scope.SQL) > 0 {
+		scope.traced = true
 		scope.db.Slog(scope.SQL, t, scope.SQLVars...)
 	}
 }
@@ -1106,29 +1337,11 @@ func (scope *Scope) createJoinTable(field *StructField) {
 		joinTableHandler := relationship.JoinTableHandler
 		joinTable := joinTableHandler.Table(scope.db)
 		if !scope.Dialect().HasTable(joinTable) {
-			toScope := &Scope{Value: reflect.New(field.Struct.Type).Interface()}
-
 			var sqlTypes, primaryKeys []string
-			for idx, fieldName := range relationship.ForeignFieldNames {
-				if field, ok := scope.FieldByName(fieldName); ok {
-					foreignKeyStruct := field.clone()
-					foreignKeyStruct.IsPrimaryKey = false
-					foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
-					delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
-					sqlTypes = append(sqlTypes, scope.Quote(relationship.ForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct))
-					primaryKeys = append(primaryKeys, scope.Quote(relationship.ForeignDBNames[idx]))
-				}
-			}
-
-			for idx, fieldName := range relationship.AssociationForeignFieldNames {
-				if field, ok := toScope.FieldByName(fieldName); ok {
-					foreignKeyStruct := field.clone()
-					foreignKeyStruct.IsPrimaryKey = false
-					foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
-					delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
-					sqlTypes = append(sqlTypes, scope.Quote(relationship.AssociationForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct))
-					primaryKeys = append(primaryKeys, scope.Quote(relationship.AssociationForeignDBNames[idx]))
-				}
+			columns, types := scope.joinTableColumns(field)
+			for idx, column := range columns {
+				sqlTypes = append(sqlTypes, scope.Quote(column)+" "+types[idx])
+				primaryKeys = append(primaryKeys, scope.Quote(column))
 			}
 
 			scope.Err(scope.NewDB().Exec(fmt.Sprintf("CREATE TABLE %v (%v, PRIMARY KEY (%v))%s", scope.Quote(joinTable), strings.Join(sqlTypes, ","), strings.Join(primaryKeys, ","), scope.getTableOptions())).Error())
@@ -1137,6 +1350,35 @@ func (scope *Scope) createJoinTable(field *StructField) {
 	}
 }
 
+// joinTableColumns return columns and their data types of the join table of a many2many field, which compose its primary key
+func (scope *Scope) joinTableColumns(field *StructField) (columns []string, types []string) {
+	relationship := field.Relationship
+	toScope := &Scope{Value: reflect.New(field.Struct.Type).Interface()}
+
+	for idx, fieldName := range relationship.ForeignFieldNames {
+		if field, ok := scope.FieldByName(fieldName); ok {
+			foreignKeyStruct := field.clone()
+			foreignKeyStruct.IsPrimaryKey = false
+			foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
+			delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
+			columns = append(columns, relationship.ForeignDBNames[idx])
+			types = append(types, scope.Dialect().DataTypeOf(foreignKeyStruct))
+		}
+	}
+
+	for idx, fieldName := range relationship.AssociationForeignFieldNames {
+		if field, ok := toScope.FieldByName(fieldName); ok {
+			foreignKeyStruct := field.clone()
+			foreignKeyStruct.IsPrimaryKey = false
+			foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
+			delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
+			columns = append(columns, relationship.AssociationForeignDBNames[idx])
+			types = append(types, scope.Dialect().DataTypeOf(foreignKeyStruct))
+		}
+	}
+	return
+}
+
 func (scope *Scope) createTable() *Scope {
 	var tags []string
 	var primaryKeys []string
@@ -1161,6 +1403,11 @@ func (scope *Scope) createTable() *Scope {
 		scope.createJoinTable(field)
 	}
 
+	names, expressions := scope.checkConstraints()
+	for idx, name := range names {
+		tags = append(tags, fmt.Sprintf("CONSTRAINT %v CHECK (%v)", scope.Quote(name), expressions[idx]))
+	}
+
 	var primaryKeyStr string
 	if len(primaryKeys) > 0 && !primaryKeyInColumnType {
 		primaryKeyStr = fmt.Sprintf(", PRIMARY KEY (%v)", strings.Join(primaryKeys, ","))
@@ -1168,6 +1415,7 @@ func (scope *Scope) createTable() *Scope {
 
 	scope.Raw(fmt.Sprintf("CREATE TABLE %v (%v %v)%s", scope.QuotedTableName(), strings.Join(tags, ","), primaryKeyStr, scope.getTableOptions())).Exec()
 
+	scope.autoComments()
 	scope.autoIndex()
 	return scope
 }
@@ -1181,26 +1429,62 @@ func (scope *Scope) modifyColumn(column string, typ string) {
 	scope.db.AddError(scope.Dialect().ModifyColumn(scope.QuotedTableName(), scope.Quote(column), typ))
 }
 
+func (scope *Scope) renameColumn(oldName string, newName string) {
+	scope.db.AddError(scope.Dialect().RenameColumn(scope.QuotedTableName(), scope.Quote(oldName), scope.Quote(newName)))
+}
+
+func (scope *Scope) renameTable(oldName string, newName string) {
+	scope.db.AddError(scope.Dialect().RenameTable(scope.Quote(oldName), scope.Quote(newName)))
+}
+
+// alterColumnNullability change nullability of column, definition of the column is built from the field of model if found
+func (scope *Scope) alterColumnNullability(column string, nullable bool) {
+	var typ string
+	if field, ok := scope.FieldByName(column); ok && field.IsNormal {
+		// mysql declares nullable timestamps with `NULL`
+		typ = strings.TrimSuffix(scope.dataTypeWithout(field.StructField, "NOT NULL", "UNIQUE"), " NULL")
+		column = field.DBName
+	}
+	scope.db.AddError(scope.Dialect().AlterColumnNullability(scope.QuotedTableName(), scope.Quote(column), typ, nullable))
+}
+
 func (scope *Scope) dropColumn(column string) {
 	scope.Raw(fmt.Sprintf("ALTER TABLE %v DROP COLUMN %v", scope.QuotedTableName(), scope.Quote(column))).Exec()
 }
 
 func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
-	if scope.Dialect().HasIndex(scope.TableName(), indexName) {
+	index := Index{Name: indexName, Unique: unique}
+	for _, name := range column {
+		index.Columns = append(index.Columns, IndexColumn{Name: name})
+	}
+	scope.createIndex(index)
+}
+
+// createIndex create index if not exist, conditions of scope are added to its predicate
+func (scope *Scope) createIndex(index Index) {
+	if scope.Dialect().HasIndex(scope.TableName(), index.Name) {
 		return
 	}
 
-	var columns []string
-	for _, name := range column {
-		columns = append(columns, scope.quoteIfPossible(name))
+	columns := index.Columns
+	index.Columns = nil
+	for _, column := range columns {
+		if !column.Expression {
+			column.Name = scope.quoteIfPossible(column.Name)
+		}
+		index.Columns = append(index.Columns, column)
 	}
 
-	sqlCreate := "CREATE INDEX"
-	if unique {
-		sqlCreate = "CREATE UNIQUE INDEX"
+	if whereSQL := strings.TrimPrefix(scope.whereSQL(), "WHERE "); whereSQL != "" {
+		if index.Where != "" {
+			whereSQL = fmt.Sprintf("(%v) AND (%v)", index.Where, whereSQL)
+		}
+		index.Where = whereSQL
 	}
 
-	scope.Raw(fmt.Sprintf("%s %v ON %v(%v) %v", sqlCreate, indexName, scope.QuotedTableName(), strings.Join(columns, ", "), scope.whereSQL())).Exec()
+	if sql, err := scope.Dialect().CreateIndexSQL(scope.QuotedTableName(), index); scope.Err(err) == nil {
+		scope.Raw(sql).Exec()
+	}
 }
 
 func (scope *Scope) addForeignKey(field string, dest string, onDelete string, onUpdate string) {
@@ -1214,6 +1498,120 @@ func (scope *Scope) addForeignKey(field string, dest string, onDelete string, on
 	scope.Raw(fmt.Sprintf(query, scope.QuotedTableName(), scope.quoteIfPossible(keyName), scope.quoteIfPossible(field), dest, onDelete, onUpdate)).Exec()
 }
 
+// autoForeignKeys add foreign key constraints of models' relationships, tables of models are considered existing
+func autoForeignKeys(db Repository, values []interface{}) Repository {
+	migratedTables := map[string]bool{}
+	for _, value := range values {
+		migratedTables[db.NewScope(value).TableName()] = true
+	}
+
+	for _, value := range values {
+		db = db.NewScope(value).autoForeignKeys(migratedTables).db
+	}
+	return db
+}
+
+// autoForeignKeys add foreign key constraints for belongs to, has one, has many and many to many relationships,
+// polymorphic relationships are skipped as they reference multiple tables
+func (scope *Scope) autoForeignKeys(migratedTables map[string]bool) *Scope {
+	if !scope.Dialect().SupportForeignKey() || scope.HasError() {
+		return scope
+	}
+
+	if value, ok := scope.Get("gorm:auto_foreign_keys"); ok && value == false {
+		return scope
+	}
+
+	exists := func(tableName string) bool {
+		return migratedTables[tableName] || scope.Dialect().HasTable(tableName)
+	}
+
+	for _, field := range scope.GetModelStruct().StructFields {
+		relationship := field.Relationship
+		if relationship == nil || relationship.PolymorphicType != "" {
+			continue
+		}
+
+		var (
+			onDelete, onUpdate = parseConstraintTagSetting(field.TagSettings["CONSTRAINT"])
+			toScope            = scope.New(reflect.New(field.Struct.Type).Interface())
+		)
+
+		switch relationship.Kind {
+		case "belongs_to":
+			if exists(toScope.TableName()) {
+				scope.addForeignKey(toForeignKeyColumns(scope, relationship.ForeignDBNames), toForeignKeyReference(toScope, relationship.AssociationForeignDBNames), onDelete, onUpdate)
+			}
+		case "has_one", "has_many":
+			if exists(toScope.TableName()) {
+				toScope.addForeignKey(toForeignKeyColumns(toScope, relationship.ForeignDBNames), toForeignKeyReference(scope, relationship.AssociationForeignDBNames), onDelete, onUpdate)
+				scope.Err(toScope.db.Error())
+			}
+		case "many_to_many":
+			handler := relationship.JoinTableHandler
+			joinScope := scope.NewDB().Table(handler.Table(scope.db)).NewScope(handler)
+
+			var sourceColumns, sourceReferences, destinationColumns, destinationReferences []string
+			for _, foreignKey := range handler.SourceForeignKeys() {
+				sourceColumns = append(sourceColumns, foreignKey.DBName)
+				sourceReferences = append(sourceReferences, foreignKey.AssociationDBName)
+			}
+			for _, foreignKey := range handler.DestinationForeignKeys() {
+				destinationColumns = append(destinationColumns, foreignKey.DBName)
+				destinationReferences = append(destinationReferences, foreignKey.AssociationDBName)
+			}
+
+			joinScope.addForeignKey(toForeignKeyColumns(joinScope, sourceColumns), toForeignKeyReference(scope, sourceReferences), onDelete, onUpdate)
+			if exists(toScope.TableName()) {
+				joinScope.addForeignKey(toForeignKeyColumns(joinScope, destinationColumns), toForeignKeyReference(toScope, destinationReferences), onDelete, onUpdate)
+			}
+			scope.Err(joinScope.db.Error())
+		}
+	}
+	return scope
+}
+
+// parseConstraintTagSetting parse tag setting like `OnDelete:CASCADE,OnUpdate:SET NULL`, actions default to `NO ACTION`
+func parseConstraintTagSetting(str string) (onDelete string, onUpdate string) {
+	onDelete, onUpdate = "NO ACTION", "NO ACTION"
+	for _, option := range strings.Split(str, ",") {
+		values := strings.SplitN(option, ":", 2)
+		if len(values) != 2 {
+			continue
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(values[0])) {
+		case "ONDELETE":
+			onDelete = strings.ToUpper(strings.TrimSpace(values[1]))
+		case "ONUPDATE":
+			onUpdate = strings.ToUpper(strings.TrimSpace(values[1]))
+		}
+	}
+	return
+}
+
+// toForeignKeyColumns return the field argument of `addForeignKey`, multiple columns are quoted as they can't be quoted by it
+func toForeignKeyColumns(scope *Scope, columns []string) string {
+	if len(columns) == 1 {
+		return columns[0]
+	}
+
+	var quotedColumns []string
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, scope.Quote(column))
+	}
+	return strings.Join(quotedColumns, ",")
+}
+
+// toForeignKeyReference return the dest argument of `addForeignKey`, e.g. `"users"("id")`
+func toForeignKeyReference(scope *Scope, columns []string) string {
+	var quotedColumns []string
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, scope.Quote(column))
+	}
+	return fmt.Sprintf("%v(%v)", scope.QuotedTableName(), strings.Join(quotedColumns, ","))
+}
+
 func (scope *Scope) removeForeignKey(field string, dest string) {
 	keyName := scope.Dialect().BuildKeyName(scope.TableName(), field, dest, "foreign")
 	if !scope.Dialect().HasForeignKey(scope.TableName(), keyName) {
@@ -1241,56 +1639,216 @@ func (scope *Scope) autoMigrate() *Scope {
 	if !scope.Dialect().HasTable(tableName) {
 		scope.createTable()
 	} else {
+		var columnTypes = map[string]ColumnType{}
+		if value, ok := scope.Get("gorm:auto_migrate_modify_columns"); ok && value == true {
+			existingColumnTypes, err := scope.Dialect().ColumnTypes(tableName)
+			if scope.Err(err) != nil {
+				return scope
+			}
+
+			for _, columnType := range existingColumnTypes {
+				columnTypes[columnType.Name] = columnType
+			}
+		}
+
 		for _, field := range scope.GetModelStruct().StructFields {
 			if !scope.Dialect().HasColumn(tableName, field.DBName) {
 				if field.IsNormal {
 					sqlTag := scope.Dialect().DataTypeOf(field)
 					scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", quotedTableName, scope.Quote(field.DBName), sqlTag)).Exec()
 				}
+			} else if columnType, ok := columnTypes[field.DBName]; ok && field.IsNormal {
+				scope.modifyColumnIfChanged(field, columnType)
 			}
 			scope.createJoinTable(field)
 		}
+		scope.autoCheckConstraints()
+		scope.autoComments()
 		scope.autoIndex()
 	}
 	return scope
 }
 
-func (scope *Scope) autoIndex() *Scope {
-	var indexes = map[string][]string{}
-	var uniqueIndexes = map[string][]string{}
+// modifyColumnIfChanged modify column if its type, size or nullability differ from field's definition, changes which may lose
+// data or fail with existing data (changing type, shrinking size, adding `NOT NULL`) are only applied with `gorm:auto_migrate_destructive`
+func (scope *Scope) modifyColumnIfChanged(field *StructField, columnType ColumnType) {
+	sqlType := scope.Dialect().DataTypeOf(field)
+	if field.IsPrimaryKey || strings.Contains(strings.ToUpper(sqlType), "AUTO_INCREMENT") {
+		return
+	}
 
-	for _, field := range scope.GetStructFields() {
-		if name, ok := field.TagSettings["INDEX"]; ok {
-			names := strings.Split(name, ",")
+	sqlType = scope.dataTypeWithout(field, "NOT NULL", "UNIQUE", "DEFAULT", "COMMENT")
+	_, notNull := field.TagSettings["NOT NULL"]
+	notNull = notNull || strings.Contains(strings.ToUpper(sqlType), "NOT NULL")
 
-			for _, name := range names {
-				if name == "INDEX" || name == "" {
-					name = scope.Dialect().BuildKeyName("idx", scope.TableName(), field.DBName)
-				}
-				indexes[name] = append(indexes[name], field.DBName)
+	var (
+		typeName, size = splitColumnType(sqlType)
+		changed        bool
+		destructive    bool
+	)
+
+	if normalizeColumnType(typeName) != normalizeColumnType(columnType.DatabaseType) {
+		changed, destructive = true, true
+	} else if size > 0 && columnType.Size > 0 && size != columnType.Size {
+		changed, destructive = true, size < columnType.Size
+	}
+
+	if notNull == columnType.Nullable {
+		changed, destructive = true, destructive || notNull
+	}
+
+	if !changed {
+		return
+	}
+
+	if destructive {
+		if value, ok := scope.Get("gorm:auto_migrate_destructive"); !ok || value != true {
+			scope.warn("skipped destructive change of column %v.%v to %v", scope.TableName(), field.DBName, sqlType)
+			return
+		}
+	}
+
+	if notNull {
+		if !strings.Contains(strings.ToUpper(sqlType), "NOT NULL") {
+			// mysql declares nullable timestamps with `NULL`
+			sqlType = strings.TrimSuffix(sqlType, " NULL") + " NOT NULL"
+		}
+	} else if !strings.HasSuffix(strings.ToUpper(sqlType), " NULL") {
+		sqlType += " NULL"
+	}
+
+	if value, ok := field.TagSettings["DEFAULT"]; ok {
+		sqlType += " DEFAULT " + value
+	}
+	scope.modifyColumn(field.DBName, sqlType)
+}
+
+// dataTypeWithout return field's data type without given tag settings, e.g. `NOT NULL`, `DEFAULT`
+func (scope *Scope) dataTypeWithout(field *StructField, settings ...string) string {
+	clone := field.clone()
+	for _, setting := range settings {
+		delete(clone.TagSettings, setting)
+	}
+	return scope.Dialect().DataTypeOf(clone)
+}
+
+// checkConstraints return names and expressions of check constraints specified by tag setting `CHECK`
+func (scope *Scope) checkConstraints() (names []string, expressions []string) {
+	for _, field := range scope.GetModelStruct().StructFields {
+		if value, ok := field.TagSettings["CHECK"]; ok && field.IsNormal {
+			name, expression := parseCheckTagSetting(value)
+			if name == "" {
+				name = scope.Dialect().BuildKeyName("chk", scope.TableName(), field.DBName)
 			}
+			names = append(names, name)
+			expressions = append(expressions, expression)
 		}
+	}
+	return
+}
 
-		if name, ok := field.TagSettings["UNIQUE_INDEX"]; ok {
-			names := strings.Split(name, ",")
+// autoCheckConstraints add missing check constraints to an existing table
+func (scope *Scope) autoCheckConstraints() {
+	if dialect := scope.Dialect(); !dialect.SupportAddConstraint() || !dialect.SupportCheckConstraint() {
+		return
+	}
 
-			for _, name := range names {
-				if name == "UNIQUE_INDEX" || name == "" {
-					name = scope.Dialect().BuildKeyName("uix", scope.TableName(), field.DBName)
-				}
-				uniqueIndexes[name] = append(uniqueIndexes[name], field.DBName)
+	names, expressions := scope.checkConstraints()
+	for idx, name := range names {
+		if !scope.Dialect().HasConstraint(scope.TableName(), name) {
+			scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v CHECK (%v)", scope.QuotedTableName(), scope.Quote(name), expressions[idx])).Exec()
+		}
+	}
+}
+
+// autoComments comment table with `TableComment()` of model, and columns with tag setting `COMMENT`, unless they are already
+// commented as expected, or comments are rendered inline by the data types of new columns
+func (scope *Scope) autoComments() {
+	var (
+		dialect   = scope.Dialect()
+		tableName = scope.TableName()
+	)
+
+	if commenter, ok := scope.Value.(tableCommenter); ok {
+		if comment := commenter.TableComment(); comment != "" && dialect.TableComment(tableName) != comment {
+			if sql := dialect.TableCommentSQL(scope.QuotedTableName(), comment); sql != "" {
+				scope.Raw(sql).Exec()
 			}
 		}
 	}
 
-	for name, columns := range indexes {
-		if db := scope.NewDB().Table(scope.TableName()).Model(scope.Value).AddIndex(name, columns...); db.Error() != nil {
-			scope.db.AddError(db.Error())
+	var commentedFields []*StructField
+	for _, field := range scope.GetModelStruct().StructFields {
+		if _, ok := field.TagSettings["COMMENT"]; ok && field.IsNormal {
+			commentedFields = append(commentedFields, field)
+		}
+	}
+
+	if len(commentedFields) == 0 {
+		return
+	}
+
+	columnTypes, err := dialect.ColumnTypes(tableName)
+	if scope.Err(err) != nil {
+		return
+	}
+
+	for _, field := range commentedFields {
+		var (
+			comment            = field.TagSettings["COMMENT"]
+			definition         = scope.dataTypeWithout(field, "UNIQUE", "COMMENT")
+			columnType, exists = findColumnType(columnTypes, field.DBName)
+		)
+
+		if exists && columnType.Comment == comment {
+			continue
+		}
+
+		if !exists && dialect.DataTypeOf(field) != scope.dataTypeWithout(field, "COMMENT") {
+			continue
+		}
+
+		if sql := dialect.ColumnCommentSQL(scope.QuotedTableName(), scope.Quote(field.DBName), definition, comment); sql != "" {
+			scope.Raw(sql).Exec()
+		}
+	}
+}
+
+func findColumnType(columnTypes []ColumnType, name string) (ColumnType, bool) {
+	for _, columnType := range columnTypes {
+		if columnType.Name == name {
+			return columnType, true
 		}
 	}
+	return ColumnType{}, false
+}
 
-	for name, columns := range uniqueIndexes {
-		if db := scope.NewDB().Table(scope.TableName()).Model(scope.Value).AddUniqueIndex(name, columns...); db.Error() != nil {
+// normalizeColumnType map aliases of a type name to the same name
+func normalizeColumnType(typeName string) string {
+	if alias, ok := columnTypeAliases[typeName]; ok {
+		return alias
+	}
+	return typeName
+}
+
+var columnTypeAliases = map[string]string{
+	"int":               "integer",
+	"int4":              "integer",
+	"serial":            "integer",
+	"int8":              "bigint",
+	"bigserial":         "bigint",
+	"bool":              "boolean",
+	"character varying": "varchar",
+	"character":         "char",
+	"double precision":  "double",
+	"float8":            "double",
+	"decimal":           "numeric",
+	"timestamptz":       "timestamp with time zone",
+}
+
+func (scope *Scope) autoIndex() *Scope {
+	for _, index := range scope.modelIndexes() {
+		if db := scope.NewDB().Table(scope.TableName()).Model(scope.Value).CreateIndex(*index); db.Error() != nil {
 			scope.db.AddError(db.Error())
 		}
 	}
@@ -1298,6 +1856,63 @@ func (scope *Scope) autoIndex() *Scope {
 	return scope
 }
 
+// modelIndexes return indexes specified by tag settings `INDEX` and `UNIQUE_INDEX` of the model, in order of declaration
+func (scope *Scope) modelIndexes() []*Index {
+	var (
+		indexes    []*Index
+		indexByKey = map[string]*Index{}
+		priorities = map[*Index][]int{}
+	)
+
+	for _, field := range scope.GetStructFields() {
+		for _, tag := range []struct {
+			key, prefix string
+			unique      bool
+		}{{"INDEX", "idx", false}, {"UNIQUE_INDEX", "uix", true}} {
+			value, ok := field.TagSettings[tag.key]
+			if !ok {
+				continue
+			}
+
+			for _, setting := range parseIndexTagSetting(value) {
+				if setting.Name == tag.key || setting.Name == "" {
+					setting.Name = scope.Dialect().BuildKeyName(tag.prefix, scope.TableName(), field.DBName)
+				}
+
+				key := fmt.Sprintf("%v/%v", tag.unique, setting.Name)
+				index, ok := indexByKey[key]
+				if !ok {
+					index = &Index{Name: setting.Name, Unique: tag.unique}
+					indexByKey[key] = index
+					indexes = append(indexes, index)
+				}
+
+				if setting.Type != "" {
+					index.Type = setting.Type
+				}
+				if setting.Where != "" {
+					index.Where = setting.Where
+				}
+
+				column := IndexColumn{Name: field.DBName, Sort: setting.Sort}
+				if setting.Expression != "" {
+					column = IndexColumn{Name: setting.Expression, Expression: true, Sort: setting.Sort}
+				}
+
+				// keep columns sorted by priority, columns with the same priority are in order of declaration
+				position := len(index.Columns)
+				for position > 0 && priorities[index][position-1] > setting.Priority {
+					position--
+				}
+				index.Columns = append(index.Columns[:position], append([]IndexColumn{column}, index.Columns[position:]...)...)
+				priorities[index] = append(priorities[index][:position], append([]int{setting.Priority}, priorities[index][position:]...)...)
+			}
+		}
+	}
+
+	return indexes
+}
+
 func (scope *Scope) getColumnAsArray(columns []string, values ...interface{}) (results [][]interface{}) {
 	for _, value := range values {
 		indirectValue := indirect(reflect.ValueOf(value))
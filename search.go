@@ -111,6 +111,11 @@ func (s *Search) Joins(query string, values ...interface{}) *Search {
 	return s
 }
 
+func (s *Search) relationshipJoins(joinType string, relationship string, values ...interface{}) *Search {
+	s.joinConditions = append(s.joinConditions, map[string]interface{}{"query": relationship, "args": values, "join": joinType})
+	return s
+}
+
 func (s *Search) Preload(schema string, values ...interface{}) *Search {
 	var preloads []searchPreload
 	for _, preload := range s.preload {
@@ -0,0 +1,300 @@
+package gorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Snapshot schema that models would produce, which is serialized as stable JSON to be committed with migrations, refer `NewSnapshot`, `CheckDrift`
+type Snapshot struct {
+	// Dialect name of the dialect building data types of columns
+	Dialect string          `json:"dialect"`
+	Tables  []SnapshotTable `json:"tables"`
+}
+
+// SnapshotTable a table of snapshot, including join tables of many2many relationships
+type SnapshotTable struct {
+	Name      string           `json:"name"`
+	JoinTable bool             `json:"join_table,omitempty"`
+	Columns   []SnapshotColumn `json:"columns"`
+	Indexes   []Index          `json:"indexes,omitempty"`
+}
+
+// SnapshotColumn a column of snapshot table
+type SnapshotColumn struct {
+	Name string `json:"name"`
+	// Type data type of the column built by `Dialect.DataTypeOf`, e.g. `varchar(100) NOT NULL`
+	Type       string `json:"type"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+}
+
+// Drift a difference between a snapshot and the database, refer `CheckDrift`
+type Drift struct {
+	Table string
+	// Column, Index name of the drifted column or index, both are empty if the table is missing
+	Column string
+	Index  string
+	// Expected, Actual description of the snapshot and the database, empty if the object is missing or unexpected
+	Expected string
+	Actual   string
+	Message  string
+}
+
+func (drift Drift) String() string {
+	var object = drift.Table
+	if drift.Column != "" {
+		object += "." + drift.Column
+	} else if drift.Index != "" {
+		object += " index " + drift.Index
+	}
+
+	if drift.Expected != "" || drift.Actual != "" {
+		return fmt.Sprintf("%v: %v, expects %v but got %v", object, drift.Message, drift.Expected, drift.Actual)
+	}
+	return fmt.Sprintf("%v: %v", object, drift.Message)
+}
+
+// NewSnapshot build snapshot of the schema that `AutoMigrate` would produce for given models, tables are ordered by name
+//     snapshot := gorm.NewSnapshot(db, &User{}, &Product{})
+//     err := snapshot.WriteJSON(file)
+func NewSnapshot(db Repository, values ...interface{}) *Snapshot {
+	var (
+		snapshot = &Snapshot{Dialect: db.Dialect().GetName()}
+		tables   = map[string]bool{}
+	)
+
+	addTable := func(table SnapshotTable) {
+		if !tables[table.Name] {
+			tables[table.Name] = true
+			snapshot.Tables = append(snapshot.Tables, table)
+		}
+	}
+
+	for _, value := range values {
+		scope := db.NewScope(value)
+		table := SnapshotTable{Name: scope.TableName(), Columns: snapshotColumns(scope)}
+		for _, index := range scope.modelIndexes() {
+			table.Indexes = append(table.Indexes, *index)
+		}
+		sort.Slice(table.Indexes, func(i, j int) bool { return table.Indexes[i].Name < table.Indexes[j].Name })
+		addTable(table)
+
+		for _, field := range scope.GetModelStruct().StructFields {
+			if relationship := field.Relationship; relationship != nil && relationship.JoinTableHandler != nil {
+				joinTable := SnapshotTable{Name: relationship.JoinTableHandler.Table(scope.db), JoinTable: true}
+				columns, types := scope.joinTableColumns(field)
+				for idx, column := range columns {
+					joinTable.Columns = append(joinTable.Columns, SnapshotColumn{Name: column, Type: types[idx], PrimaryKey: true})
+				}
+
+				// columns of customized join table handlers
+				for _, column := range snapshotColumns(scope.NewDB().Table(joinTable.Name).NewScope(relationship.JoinTableHandler)) {
+					if _, ok := joinTable.column(column.Name); !ok {
+						joinTable.Columns = append(joinTable.Columns, column)
+					}
+				}
+				addTable(joinTable)
+			}
+		}
+	}
+
+	sort.Slice(snapshot.Tables, func(i, j int) bool { return snapshot.Tables[i].Name < snapshot.Tables[j].Name })
+	return snapshot
+}
+
+func snapshotColumns(scope *Scope) (columns []SnapshotColumn) {
+	for _, field := range scope.GetModelStruct().StructFields {
+		if field.IsNormal {
+			columns = append(columns, SnapshotColumn{Name: field.DBName, Type: scope.Dialect().DataTypeOf(field), PrimaryKey: field.IsPrimaryKey})
+		}
+	}
+	return
+}
+
+// ReadSnapshot read snapshot written by `Snapshot.WriteJSON`
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("gorm: invalid snapshot, got %v", err)
+	}
+	return &snapshot, nil
+}
+
+// WriteJSON write snapshot as indented JSON, which is the same for the same schema
+func (snapshot *Snapshot) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func (table *SnapshotTable) column(name string) (SnapshotColumn, bool) {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return SnapshotColumn{}, false
+}
+
+// CheckDrift compare snapshot with the current database, and return missing tables, missing or unexpected columns and indexes, and
+// columns or indexes differing in type, size, nullability, uniqueness or columns; tables not in the snapshot are ignored.
+// In CI, run migrations on an empty database and check it against the snapshot of models to find model changes without migrations;
+// check the production database against the committed snapshot to find changes applied directly
+//     drifts, err := gorm.CheckDrift(db, gorm.NewSnapshot(db, &User{}, &Product{}))
+func CheckDrift(db Repository, snapshot *Snapshot) ([]Drift, error) {
+	dialect := db.Dialect()
+	if snapshot.Dialect != dialect.GetName() {
+		return nil, fmt.Errorf("gorm: snapshot of dialect %v can't be checked against %v", snapshot.Dialect, dialect.GetName())
+	}
+
+	var drifts []Drift
+	for _, table := range snapshot.Tables {
+		if !dialect.HasTable(table.Name) {
+			drifts = append(drifts, Drift{Table: table.Name, Message: "table is missing"})
+			continue
+		}
+
+		columnTypes, err := dialect.ColumnTypes(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, columnDrifts(table, columnTypes)...)
+
+		indexes, err := dialect.Indexes(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		foreignKeys, err := dialect.ForeignKeys(table.Name)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, indexDrifts(dialect, table, indexes, foreignKeys)...)
+	}
+	return drifts, nil
+}
+
+func columnDrifts(table SnapshotTable, columnTypes []ColumnType) (drifts []Drift) {
+	for _, column := range table.Columns {
+		columnType, ok := findColumnType(columnTypes, column.Name)
+		if !ok {
+			drifts = append(drifts, Drift{Table: table.Name, Column: column.Name, Message: "column is missing"})
+			continue
+		}
+
+		typeName, size := splitColumnType(column.Type)
+		if normalizeColumnType(typeName) != normalizeColumnType(columnType.DatabaseType) || (size > 0 && columnType.Size > 0 && size != columnType.Size) {
+			drifts = append(drifts, Drift{Table: table.Name, Column: column.Name, Message: "type differs", Expected: describeColumnType(typeName, size), Actual: describeColumnType(columnType.DatabaseType, columnType.Size)})
+		}
+
+		if nullable := !strings.Contains(strings.ToUpper(column.Type), "NOT NULL"); !column.PrimaryKey && nullable != columnType.Nullable {
+			drifts = append(drifts, Drift{Table: table.Name, Column: column.Name, Message: "nullability differs", Expected: nullabilitySQL(nullable), Actual: nullabilitySQL(columnType.Nullable)})
+		}
+	}
+
+	for _, columnType := range columnTypes {
+		if _, ok := table.column(columnType.Name); !ok {
+			drifts = append(drifts, Drift{Table: table.Name, Column: columnType.Name, Message: "unexpected column"})
+		}
+	}
+	return
+}
+
+func describeColumnType(typeName string, size int) string {
+	if size > 0 {
+		return fmt.Sprintf("%v(%d)", typeName, size)
+	}
+	return typeName
+}
+
+// indexDrifts compare indexes of snapshot table with indexes in database, indexes created implicitly for unique columns and foreign
+// keys are not unexpected; predicates and types of indexes are not compared as databases rewrite them
+func indexDrifts(dialect Dialect, table SnapshotTable, indexes []Index, foreignKeys []ForeignKey) (drifts []Drift) {
+	existing := map[string]Index{}
+	for _, index := range indexes {
+		existing[index.Name] = index
+	}
+
+	expected := map[string]bool{}
+	for _, index := range table.Indexes {
+		expected[index.Name] = true
+
+		current, ok := existing[index.Name]
+		if !ok {
+			drifts = append(drifts, Drift{Table: table.Name, Index: index.Name, Message: "index is missing"})
+		} else if !sameIndex(index, current) {
+			drifts = append(drifts, Drift{Table: table.Name, Index: index.Name, Message: "index differs", Expected: describeIndex(index), Actual: describeIndex(current)})
+		}
+	}
+
+	for _, index := range indexes {
+		if expected[index.Name] || isImplicitIndex(dialect, table, index, foreignKeys) {
+			continue
+		}
+		drifts = append(drifts, Drift{Table: table.Name, Index: index.Name, Message: "unexpected index"})
+	}
+	return
+}
+
+// sameIndex compare uniqueness and columns of indexes, texts of expressions are only compared in kind
+func sameIndex(index Index, current Index) bool {
+	if index.Unique != current.Unique || len(index.Columns) != len(current.Columns) {
+		return false
+	}
+
+	for idx, column := range index.Columns {
+		currentColumn := current.Columns[idx]
+		if column.Expression != currentColumn.Expression || indexColumnSort(column) != indexColumnSort(currentColumn) {
+			return false
+		}
+		if !column.Expression && column.Name != currentColumn.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// indexColumnSort return `DESC` or empty for ascending columns
+func indexColumnSort(column IndexColumn) string {
+	if sort := strings.ToUpper(column.Sort); sort == "DESC" {
+		return sort
+	}
+	return ""
+}
+
+func describeIndex(index Index) string {
+	var columns []string
+	for _, column := range index.Columns {
+		columns = append(columns, strings.TrimSpace(column.Name+" "+column.Sort))
+	}
+
+	if index.Unique {
+		return fmt.Sprintf("UNIQUE (%v)", strings.Join(columns, ", "))
+	}
+	return fmt.Sprintf("(%v)", strings.Join(columns, ", "))
+}
+
+// isImplicitIndex check index is created by the database for a unique column or a foreign key
+func isImplicitIndex(dialect Dialect, table SnapshotTable, index Index, foreignKeys []ForeignKey) bool {
+	if dialect.IsImplicitIndex(index) {
+		return true
+	}
+
+	for _, foreignKey := range foreignKeys {
+		if foreignKey.Name == index.Name {
+			return true
+		}
+	}
+
+	if index.Unique && len(index.Columns) == 1 {
+		if column, ok := table.column(index.Columns[0].Name); ok {
+			return strings.Contains(strings.ToUpper(column.Type), "UNIQUE") || column.PrimaryKey
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package gorm_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type SnapshotAuthor struct {
+	ID    uint
+	Name  string        `gorm:"size:100;not null;index:idx_snapshot_authors_name"`
+	Email string        `gorm:"unique_index"`
+	Tags  []SnapshotTag `gorm:"many2many:snapshot_author_tags"`
+}
+
+type SnapshotTag struct {
+	ID   uint
+	Name string
+}
+
+func TestSnapshot(t *testing.T) {
+	snapshot := gorm.NewSnapshot(DB, &SnapshotTag{}, &SnapshotAuthor{})
+
+	var names []string
+	for _, table := range snapshot.Tables {
+		names = append(names, table.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"snapshot_author_tags", "snapshot_authors", "snapshot_tags"}) {
+		t.Errorf("Snapshot should contain tables and join tables ordered by name, but got %v", names)
+	}
+
+	if table := snapshot.Tables[0]; !table.JoinTable || len(table.Columns) != 2 || !table.Columns[0].PrimaryKey {
+		t.Errorf("Join table's columns should be its primary keys, but got %+v", table)
+	}
+
+	author := snapshot.Tables[1]
+	if len(author.Columns) != 3 || author.Columns[1].Name != "name" || author.Columns[1].Type != DB.Dialect().DataTypeOf(DB.NewScope(&SnapshotAuthor{}).GetModelStruct().StructFields[1]) {
+		t.Errorf("Snapshot columns should be built with DataTypeOf, but got %+v", author.Columns)
+	}
+	if len(author.Indexes) != 2 || author.Indexes[0].Name != "idx_snapshot_authors_name" || !author.Indexes[1].Unique {
+		t.Errorf("Snapshot indexes should be ordered by name, but got %+v", author.Indexes)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.WriteJSON(&buf); err != nil {
+		t.Fatalf("No error should happen when write snapshot, but got %v", err)
+	}
+
+	var again bytes.Buffer
+	gorm.NewSnapshot(DB, &SnapshotAuthor{}, &SnapshotTag{}).WriteJSON(&again)
+	if buf.String() != again.String() {
+		t.Errorf("Snapshot should be stable, expects\n%v\nbut got\n%v", buf.String(), again.String())
+	}
+
+	read, err := gorm.ReadSnapshot(&buf)
+	if err != nil || !reflect.DeepEqual(read, snapshot) {
+		t.Errorf("Snapshot should be read as written, but got %+v, %v", read, err)
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	DB.DropTableIfExists(&SnapshotAuthor{}, &SnapshotTag{}, "snapshot_author_tags")
+	snapshot := gorm.NewSnapshot(DB, &SnapshotAuthor{}, &SnapshotTag{})
+
+	drifts, err := gorm.CheckDrift(DB, snapshot)
+	if err != nil || len(drifts) != 3 || drifts[0].Message != "table is missing" {
+		t.Errorf("Missing tables should drift, but got %v, %v", drifts, err)
+	}
+
+	DB.AutoMigrate(&SnapshotAuthor{}, &SnapshotTag{})
+	if drifts, err := gorm.CheckDrift(DB, snapshot); err != nil || len(drifts) != 0 {
+		t.Errorf("Migrated tables should not drift, but got %v, %v", drifts, err)
+	}
+
+	DB.Model(&SnapshotTag{}).AddIndex("idx_snapshot_tags_name", "name")
+	DB.Exec("ALTER TABLE snapshot_tags ADD hotfix varchar(10)")
+	DB.Model(&SnapshotAuthor{}).RemoveIndex("idx_snapshot_authors_name")
+
+	drifts, err = gorm.CheckDrift(DB, snapshot)
+	if err != nil {
+		t.Fatalf("No error should happen when check drift, but got %v", err)
+	}
+
+	var messages []string
+	for _, drift := range drifts {
+		messages = append(messages, drift.String())
+	}
+	expected := []string{
+		"snapshot_authors index idx_snapshot_authors_name: index is missing",
+		"snapshot_tags.hotfix: unexpected column",
+		"snapshot_tags index idx_snapshot_tags_name: unexpected index",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Changes applied directly should drift, expects %v but got %v", expected, messages)
+	}
+
+	if _, err := gorm.CheckDrift(DB, &gorm.Snapshot{Dialect: "unknown"}); err == nil {
+		t.Errorf("Should return error when check snapshot of another dialect")
+	}
+}